@@ -0,0 +1,92 @@
+// Copyright © 2018 Vinit Kumar Rai <vinitrai.marshal@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	tree "github.com/marshal003/hitree/core"
+	"github.com/spf13/cobra"
+)
+
+// diffCmd compares two directories or JSON snapshots
+var diffCmd = &cobra.Command{
+	Use:   "diff <a> <b>",
+	Short: "Print merged tree of two directories or JSON snapshots marking the changes",
+	Long: `Compare two directories, or two JSON snapshots exported with --json --includestats,
+and print a single merged tree where added entries are marked with +, removed
+with - and modified (by size, modification time, permission or digest) with ~.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nocolor, _ := cmd.Flags().GetBool("nocolor")
+		tree.InitAurora(!nocolor)
+		opt.IncludeHidden, _ = cmd.Flags().GetBool("all")
+		opt.NoReport, _ = cmd.Flags().GetBool("noreport")
+		opt.Charset, _ = cmd.Flags().GetString("charset")
+		if err := tree.ValidateCharset(opt.Charset); err != nil {
+			return err
+		}
+		opt.Hash, _ = cmd.Flags().GetString("hash")
+		if err := tree.ValidateHash(opt.Hash); err != nil {
+			return err
+		}
+		dopt := tree.DefaultDiffOptions()
+		dopt.OnlyChanges, _ = cmd.Flags().GetBool("changes")
+		dopt.Compare, _ = cmd.Flags().GetStringSlice("compare")
+		if err := tree.ValidateDiffAttributes(dopt.Compare); err != nil {
+			return err
+		}
+
+		a, err := loadTree(args[0])
+		if err != nil {
+			return err
+		}
+		b, err := loadTree(args[1])
+		if err != nil {
+			return err
+		}
+		diff, stats := tree.Diff(a, b, dopt)
+		w := openOutput()
+		defer w.Close()
+		diff.Print(w, stats, opt, dopt)
+		return nil
+	},
+}
+
+// loadTree loads the tree from JSON snapshot if path is a .json file,
+// otherwise traverses the directory
+func loadTree(path string) (tree.Tree, error) {
+	if fi, err := os.Stat(path); err == nil && !fi.IsDir() && strings.HasSuffix(path, ".json") {
+		root, err := tree.LoadJSONFile(path)
+		if err != nil {
+			return root, err
+		}
+		return root.Reshape(opt)
+	}
+	return tree.TraverseDir(path, opt, 0)
+}
+
+func init() {
+	diffCmd.Flags().SortFlags = false
+	diffCmd.Flags().BoolP("all", "a", false, "Compare hidden files & directories too")
+	diffCmd.Flags().Bool("changes", false, "Omit unchanged entries")
+	diffCmd.Flags().StringSlice("compare", nil, "Attributes to compare: size, mtime, perm, digest (default all)")
+	diffCmd.Flags().String("hash", "", "Digest files with sha256, sha1, md5 or xxhash to compare their content")
+	diffCmd.Flags().String("charset", "utf8", "Characters used to draw the branches: utf8 or ascii")
+	diffCmd.Flags().BoolP("nocolor", "n", false, "Turn colorization off always")
+	diffCmd.Flags().Bool("noreport", false, "Omits printing of the summary of changes")
+	RootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,79 @@
+// Copyright © 2018 Vinit Kumar Rai <vinitrai.marshal@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+
+	tree "github.com/marshal003/hitree/core"
+	"github.com/spf13/cobra"
+)
+
+// dupesCmd lists the files having the same content
+var dupesCmd = &cobra.Command{
+	Use:   "dupes [dir]",
+	Short: "Print sets of files having the same content and the space they waste",
+	Long: `Find the files below the directory which have the same content. Files are
+grouped by size and then by digest of their content, so only the files sharing
+their size are read. Each set is printed with its files, largest waste first.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nocolor, _ := cmd.Flags().GetBool("nocolor")
+		tree.InitAurora(!nocolor)
+		opt.IncludeHidden, _ = cmd.Flags().GetBool("all")
+		opt.NoReport, _ = cmd.Flags().GetBool("noreport")
+		opt.HumanReadable, _ = cmd.Flags().GetBool("human")
+		opt.ExcludePattern, _ = cmd.Flags().GetStringSlice("excludepattern")
+		opt.UseGitIgnore, _ = cmd.Flags().GetBool("gitignore")
+		path := "."
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		root, err := tree.TraverseDir(path, opt, 0)
+		if err != nil {
+			return err
+		}
+		// Only the files sharing their size are digested, not the whole tree
+		dopt := opt
+		dopt.Hash, _ = cmd.Flags().GetString("hash")
+		sets, err := tree.FindDuplicates(root, path, dopt)
+		if err != nil {
+			return err
+		}
+		w := openOutput()
+		defer w.Close()
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(sets)
+		}
+		tree.PrintDuplicates(w, sets, opt)
+		return nil
+	},
+}
+
+func init() {
+	dupesCmd.Flags().SortFlags = false
+	dupesCmd.Flags().BoolP("all", "a", false, "Look for duplicates among hidden files & directories too")
+	dupesCmd.Flags().StringSliceP("excludepattern", "I", nil, "Skip files that match the wild-card pattern (repeatable)")
+	dupesCmd.Flags().Bool("gitignore", false, "Skip files ignored by .gitignore")
+	dupesCmd.Flags().String("hash", "sha256", "Digest used to compare the content: sha256, sha1, md5 or xxhash")
+	dupesCmd.Flags().Bool("human", false, "Print sizes in human readable units of 1024, like 4.0K, 12M")
+	dupesCmd.Flags().BoolP("json", "j", false, "Print the sets as JSON")
+	dupesCmd.Flags().BoolP("nocolor", "n", false, "Turn colorization off always")
+	dupesCmd.Flags().Bool("noreport", false, "Omits printing of the wasted space at the end")
+	RootCmd.AddCommand(dupesCmd)
+}
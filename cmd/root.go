@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"time"
 
 	tree "github.com/marshal003/hitree/core"
 	homedir "github.com/mitchellh/go-homedir"
@@ -59,6 +60,36 @@ func setColorOption(cmd *cobra.Command, colorHolder *tree.Colorize, flag string)
 	}
 }
 
+// lsColorCodes LS_COLORS codes of the nodes colored by each color flag
+var lsColorCodes = map[string][]string{
+	"dircolor":     {"di"},
+	"filecolor":    {"fi", "no"},
+	"symlinkcolor": {"ln", "or"},
+	"execcolor":    {"ex"},
+	"fifocolor":    {"pi"},
+	"socketcolor":  {"so"},
+	"devicecolor":  {"cd", "bd"},
+}
+
+// setLSColors colors the nodes like ls when LS_COLORS is set, nodes without
+// a rule in LS_COLORS keep the colors of the flags. Color flags set on the
+// command line take precedence over LS_COLORS.
+func setLSColors(cmd *cobra.Command) {
+	colors, err := tree.LSColorsFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ignoring LS_COLORS, %v\n", err)
+		return
+	}
+	if colors != nil {
+		for flag, codes := range lsColorCodes {
+			if cmd.Flags().Changed(flag) {
+				colors.Disable(codes...)
+			}
+		}
+	}
+	opt.LSColors = colors
+}
+
 func initOptions() {
 	opt.DirOnly = viper.GetBool("dironly")
 	opt.IncludeHidden = viper.GetBool("all")
@@ -67,22 +98,85 @@ func initOptions() {
 	opt.FollowLink = viper.GetBool("followlink")
 	opt.Prune = viper.GetBool("prune")
 	opt.MaxLevel = int16(viper.GetInt("level"))
-	opt.ExcludePattern = viper.GetString("excludepattern")
-	opt.IncludePattern = viper.GetString("includepattern")
+	opt.ExcludePattern = viper.GetStringSlice("excludepattern")
+	opt.IncludePattern = viper.GetStringSlice("includepattern")
+	opt.PatternRegex = viper.GetBool("regex")
+	opt.MatchDirs = viper.GetBool("matchdirs")
+	opt.UseGitIgnore = viper.GetBool("gitignore")
 	opt.Indent = viper.GetInt("jsonindent")
 	opt.JSONIncludeStats = viper.GetBool("includestats")
 	opt.OutputPath = viper.GetString("output")
+	opt.HTMLBaseURL = viper.GetString("baseurl")
+	opt.HTMLTitle = viper.GetString("title")
+	opt.Columns = viper.GetStringSlice("columns")
+	opt.Charset = viper.GetString("charset")
+	opt.MarkdownLinks = viper.GetBool("mdlinks")
+	opt.MarkdownList = viper.GetBool("mdlist") || opt.MarkdownLinks
 	opt.FileLimit = viper.GetInt("filelimit")
-	opt.PrintGID = (runtime.GOOS == "linux" || runtime.GOOS == "darwin") && viper.GetBool("group")
-	opt.PrintUID = (runtime.GOOS == "linux" || runtime.GOOS == "darwin") && viper.GetBool("user")
-	opt.PrintSize = viper.GetBool("size")
+	opt.Jobs = viper.GetInt("jobs")
+	if opt.Jobs < 1 {
+		opt.Jobs = runtime.NumCPU()
+	}
+	opt.Hash = viper.GetString("hash")
+	opt.HashMaxSize = -1
+	opt.MinSize, opt.MaxSize = -1, -1
+	opt.MarkDuplicates = viper.GetBool("dupes")
+	opt.Empty = viper.GetBool("empty")
+	opt.Perm = viper.GetString("perm")
+	opt.OwnerFilter = viper.GetString("owner")
+	opt.GroupFilter = viper.GetString("ownergroup")
+	opt.PrintGID = viper.GetBool("group")
+	opt.PrintUID = viper.GetBool("user")
+	opt.NumericIDs = viper.GetBool("numericids")
+	opt.AggregateSize = viper.GetBool("du")
+	opt.PrintBlocks = viper.GetBool("blocks")
+	opt.HumanReadable = viper.GetBool("human")
+	opt.SIUnits = viper.GetBool("si")
+	opt.PrintSize = viper.GetBool("size") || opt.AggregateSize || opt.HumanReadable || opt.SIUnits
 	opt.PrintProtection = viper.GetBool("protection")
 	opt.PrintModTime = viper.GetBool("modtime")
+	opt.PrintChangeTime = viper.GetBool("ctime")
+	opt.PrintAccessTime = viper.GetBool("atime")
+	opt.PrintInode = viper.GetBool("inodes")
+	opt.PrintDevice = viper.GetBool("device")
+	opt.PrintLinks = viper.GetBool("nlink")
 	opt.SortReverse = viper.GetBool("reverse")
 	opt.SortByModTime = viper.GetBool("sortbymodtime")
+	opt.SortKey = viper.GetString("sort")
+	opt.DirsFirst = viper.GetBool("dirsfirst")
+	opt.FilesFirst = viper.GetBool("filesfirst")
+	opt.Classify = viper.GetBool("classify")
 	opt.TimeFormat = viper.GetString("timefmt")
 }
 
+// initPredicates parses the flags filtering entries by size, age & type
+func initPredicates() error {
+	var err error
+	if minSize := viper.GetString("minsize"); minSize != "" {
+		if opt.MinSize, err = tree.ParseSize(minSize); err != nil {
+			return err
+		}
+	}
+	if maxSize := viper.GetString("maxsize"); maxSize != "" {
+		if opt.MaxSize, err = tree.ParseSize(maxSize); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	if newer := viper.GetString("newer"); newer != "" {
+		if opt.NewerThan, err = tree.ParseTimeBound(newer, now); err != nil {
+			return err
+		}
+	}
+	if older := viper.GetString("older"); older != "" {
+		if opt.OlderThan, err = tree.ParseTimeBound(older, now); err != nil {
+			return err
+		}
+	}
+	opt.Types, err = tree.ParseFileTypes(viper.GetStringSlice("type"))
+	return err
+}
+
 // RootCmd represents the base command when called without any subcommands
 var RootCmd = &cobra.Command{
 	Use:   "hitree",
@@ -91,6 +185,8 @@ var RootCmd = &cobra.Command{
 Note: windows 10 has issue with ansi color, so for this release color output will be
 disabled on windows platform.
 	`,
+	// Accept the directory as argument, next to the subcommands
+	Args: cobra.ArbitraryArgs,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		nocolor := (viper.GetBool("nocolor") || (runtime.GOOS == "windows"))
 		tree.InitAurora(!nocolor)
@@ -101,6 +197,12 @@ disabled on windows platform.
 		setColorOption(cmd, &opt.PipeColor, "pipecolor")
 		setColorOption(cmd, &opt.TLinkColor, "tlinkcolor")
 		setColorOption(cmd, &opt.LLinkColor, "llinkcolor")
+		setColorOption(cmd, &opt.ErrorColor, "errorcolor")
+		setColorOption(cmd, &opt.ExecColor, "execcolor")
+		setColorOption(cmd, &opt.FIFOColor, "fifocolor")
+		setColorOption(cmd, &opt.SocketColor, "socketcolor")
+		setColorOption(cmd, &opt.DeviceColor, "devicecolor")
+		setLSColors(cmd)
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		showVersion, _ := cmd.Flags().GetBool("version")
@@ -113,38 +215,131 @@ disabled on windows platform.
 			path = args[0]
 		}
 
+		format, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+		renderer, err := tree.LookupRenderer(format)
+		if err != nil {
+			return err
+		}
+		if err := tree.ValidateCharset(opt.Charset); err != nil {
+			return err
+		}
+		if err := tree.ValidateHash(opt.Hash); err != nil {
+			return err
+		}
+		if maxSize := viper.GetString("hashmaxsize"); maxSize != "" {
+			if opt.HashMaxSize, err = tree.ParseSize(maxSize); err != nil {
+				return err
+			}
+		}
+		if err := initPredicates(); err != nil {
+			return err
+		}
+
+		snapshot, _ := cmd.Flags().GetString("load")
+		if snapshot != "" {
+			root, err := tree.LoadJSONFile(snapshot)
+			if err != nil {
+				return err
+			}
+			if root, err = root.Reshape(opt); err != nil {
+				return err
+			}
+			return sendOutput(renderer, root)
+		}
+
+		fromFile, _ := cmd.Flags().GetBool("fromfile")
+		if fromFile {
+			root, err := treeFromFile(args)
+			if err != nil {
+				return err
+			}
+			return sendOutput(renderer, root)
+		}
+
+		// Formats written line by line are streamed unless the whole tree is
+		// needed to prune, aggregate sizes, digest directories, find
+		// duplicates or filter by predicates, text only with --stream
+		stream, _ := cmd.Flags().GetBool("stream")
+		streamer, ok := renderer.(tree.StreamRenderer)
+		wholeTree := opt.Prune || opt.AggregateSize || opt.Hash != "" || opt.MarkDuplicates || opt.HasPredicates()
+		if ok && (stream || format != "text") && !wholeTree {
+			return streamOutput(streamer, path)
+		}
+
 		root, err := tree.TraverseDir(path, opt, 0)
 		if err != nil {
 			return err
 		}
-		return sendOutput(cmd, root)
+		return sendOutput(renderer, root)
 	},
 }
 
-func sendOutput(cmd *cobra.Command, root tree.Tree) error {
-	asJSON, _ := cmd.Flags().GetBool("json")
-	var w io.WriteCloser
+// outputFormat resolves the output format from --format, or from the
+// shorthand flags --json, --html & --xml
+func outputFormat(cmd *cobra.Command) (string, error) {
+	format, _ := cmd.Flags().GetString("format")
+	for _, shorthand := range []string{"json", "html", "xml"} {
+		if enabled, _ := cmd.Flags().GetBool(shorthand); enabled && format == "" {
+			format = shorthand
+		}
+	}
+	if format == "" {
+		return "text", nil
+	}
+	return format, nil
+}
+
+// treeFromFile builds the tree from paths listed in the file given as first
+// argument, or in stdin when it is "-" or missing
+func treeFromFile(args []string) (tree.Tree, error) {
+	name := "-"
+	if len(args) >= 1 {
+		name = args[0]
+	}
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return tree.Tree{}, err
+		}
+		defer f.Close()
+		r = f
+	}
+	paths, err := tree.ReadPaths(r)
+	if err != nil {
+		return tree.Tree{}, err
+	}
+	if name == "-" {
+		name = "."
+	}
+	return tree.TreeFromPaths(name, paths, opt)
+}
+
+func openOutput() io.WriteCloser {
 	if opt.OutputPath != "stdout" {
 		f, err := os.Create(opt.OutputPath)
 		if err != nil {
 			panic(fmt.Sprintf("Unable to open output file %v", err))
 		}
-		w = f
-	} else {
-		w = os.Stdout
+		return f
 	}
+	return os.Stdout
+}
+
+func streamOutput(streamer tree.StreamRenderer, path string) error {
+	w := openOutput()
 	defer w.Close()
+	_, err := streamer.Stream(w, path, opt)
+	return err
+}
 
-	if !asJSON {
-		root.Print(w, opt)
-		return nil
-	}
-	res, err := root.AsJSONString(opt)
-	if err != nil {
-		return err
-	}
-	fmt.Fprintf(w, "\n%s\n", res)
-	return nil
+func sendOutput(renderer tree.Renderer, root tree.Tree) error {
+	w := openOutput()
+	defer w.Close()
+	return renderer.Render(w, root, opt)
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -163,63 +358,149 @@ func init() {
 	RootCmd.PersistentFlags().SortFlags = false
 	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.hitree.yaml)")
 	RootCmd.Flags().BoolP("version", "v", false, "Version of hitree command")
+	RootCmd.Flags().String("format", "", "Output format: "+strings.Join(tree.RendererNames(), ", ")+" (default text)")
 	RootCmd.Flags().BoolP("json", "j", false, "Print Tree structure as JSON")
+	RootCmd.Flags().String("load", "", "Render the tree from a JSON snapshot exported with --json (- for stdin)")
+	RootCmd.Flags().Bool("fromfile", false, "Read paths from the file given as argument (or stdin if it is - or missing) instead of the file system")
+	RootCmd.Flags().BoolP("xml", "X", false, "Print Tree structure as XML, compatible with tree -X")
+	RootCmd.Flags().BoolP("html", "H", false, "Print Tree structure as HTML page with collapsible directories")
+	RootCmd.Flags().StringSlice("columns", nil, "Columns of csv & tsv output among path, name, depth, type, size, mode, uid, gid, mtime, digest, error (default all)")
+	RootCmd.Flags().Bool("mdlist", false, "Render markdown output as nested bullet list instead of code block")
+	RootCmd.Flags().Bool("mdlinks", false, "Link entries of markdown bullet list relative to --baseurl (implies --mdlist)")
+	RootCmd.Flags().String("baseurl", "", "Base URL of the links in HTML output")
+	RootCmd.Flags().String("title", "", "Title of the HTML page (default name of the directory)")
+	RootCmd.Flags().Bool("stream", false, "Print each entry as soon as it is read instead of building the tree first (ignored with --prune, --du, --hash, --dupes & predicates)")
 	RootCmd.Flags().Bool("includestats", false, "Include File Stats in JSON Output")
 	RootCmd.Flags().Int("jsonindent", 2, "JSON Indentation")
 	RootCmd.Flags().StringP("output", "o", "stdout", "Put result in the output file")
 	RootCmd.Flags().BoolP("dironly", "d", false, "List only directories")
 	RootCmd.Flags().BoolP("all", "a", false, "List all files & directories including hidden ones")
 	RootCmd.Flags().BoolP("fullpath", "f", false, "Print full path prefix for all files")
+	RootCmd.Flags().BoolP("classify", "F", false, "Append / for directories, @ for links, | for named pipes, = for sockets and * for executables, like ls -F")
 	RootCmd.Flags().BoolP("noreport", "", false, "Omits printing of the file and directory report at the end of the tree listing.")
 	RootCmd.Flags().BoolP("followlink", "l", false, "Follow link and list files in the link is for a directory")
 	RootCmd.Flags().BoolP("prune", "", false, "Makes tree prune empty directories from the output")
+	RootCmd.Flags().String("charset", "utf8", "Characters used to draw the branches: utf8 or ascii")
 	RootCmd.Flags().BoolP("nocolor", "n", false, "Turn colorization off always")
 	RootCmd.Flags().Int16P("level", "L", -1, "Max display depth of the directory tree")
 
 	//New
 	RootCmd.Flags().Int("filelimit", -1, "Do not descend directories that contain more than # entries.")
+	RootCmd.Flags().IntP("jobs", "J", 1, "Number of directories to read concurrently (0 uses all CPUs)")
+	RootCmd.Flags().String("hash", "", "Print digest of files & directories computed with sha256, sha1, md5 or xxhash")
+	RootCmd.Flags().String("hash-max-size", "", "Do not digest files larger than the size, like 100M (default no limit)")
+	RootCmd.Flags().Bool("dupes", false, "Mark files having the same content as a file listed before them")
 	RootCmd.Flags().String("timefmt", "Jan 2 15:04:05 PM", "Prints (implies -D) and formats the date according to the format string")
 	RootCmd.Flags().BoolP("protection", "p", false, "Print Protection on file")
 	RootCmd.Flags().BoolP("size", "s", false, "Print Size on file")
+	RootCmd.Flags().Bool("du", false, "Print size of each directory as the sum of everything under it (implies -s)")
+	RootCmd.Flags().Bool("blocks", false, "Print disk usage (allocated blocks) instead of apparent size")
+	RootCmd.Flags().Bool("human", false, "Print size in human readable units of 1024, like 4.0K, 12M (implies -s)")
+	RootCmd.Flags().Bool("si", false, "Like --human, but use powers of 1000 (implies -s)")
 	RootCmd.Flags().BoolP("user", "u", false, "Print the username, or UID")
 	RootCmd.Flags().BoolP("group", "g", false, "Print the group name, or GID")
+	RootCmd.Flags().Bool("numeric-ids", false, "Print UID & GID instead of user & group names")
 	RootCmd.Flags().BoolP("modtime", "D", false, "Print the date of the last modification time for the file listed")
-	RootCmd.Flags().BoolP("reverse", "r", false, "Sort the output in reverse alphabetic order")
-	RootCmd.Flags().BoolP("sortbymodtime", "t", false, "Sort the output by last modification time instead of alphabetically")
+	RootCmd.Flags().Bool("ctime", false, "Print the date of the last status change for the file listed")
+	RootCmd.Flags().Bool("atime", false, "Print the date of the last access for the file listed")
+	RootCmd.Flags().Bool("inodes", false, "Print the inode number of the file")
+	RootCmd.Flags().Bool("device", false, "Print the device ID of the file")
+	RootCmd.Flags().Bool("nlink", false, "Print the number of hard links to the file")
+	RootCmd.Flags().BoolP("reverse", "r", false, "Reverse the sort order")
+	RootCmd.Flags().BoolP("sortbymodtime", "t", false, "Sort the output by last modification time instead of alphabetically (same as --sort=mtime)")
+	RootCmd.Flags().String("sort", "", "Sort the output by name, size, mtime, ctime, ext, version or none (default name)")
+	RootCmd.Flags().Bool("dirsfirst", false, "List directories before files")
+	RootCmd.Flags().Bool("filesfirst", false, "List files before directories")
 
 	//Pattern flags
-	RootCmd.Flags().StringP("includepattern", "P", "", "List only those files which matches to wild-card pattern")
-	RootCmd.Flags().StringP("excludepattern", "I", "", "Do not list those files that match the wild-card pattern.")
+	RootCmd.Flags().StringSliceP("includepattern", "P", nil, "List only those files which matches to wild-card pattern, ** matches directories when pattern has a slash (repeatable)")
+	RootCmd.Flags().StringSliceP("excludepattern", "I", nil, "Do not list those files that match the wild-card pattern (repeatable)")
+	RootCmd.Flags().Bool("regex", false, "Treat include & exclude patterns as regular expressions matched against the relative path")
+	RootCmd.Flags().Bool("matchdirs", false, "Apply patterns to directory names too, content of a matching directory is listed entirely")
+	RootCmd.Flags().Bool("gitignore", false, "Do not list files ignored by .gitignore, .git/info/exclude and the global excludes file")
+
+	//Predicate flags, directories left without matching entries are not listed
+	RootCmd.Flags().String("min-size", "", "List only entries of at least the size, like 10K or 1.5G")
+	RootCmd.Flags().String("max-size", "", "List only entries of at most the size, like 10K or 1.5G")
+	RootCmd.Flags().String("newer", "", "List only entries modified after the date (2006-01-02) or within the age (36h, 7d, 2w)")
+	RootCmd.Flags().String("older", "", "List only entries modified before the date (2006-01-02) or more than the age ago (36h, 7d, 2w)")
+	RootCmd.Flags().StringSlice("type", nil, "List only entries of the types: f (file), d (dir), l (link), p (pipe), s (socket), c & b (devices)")
+	RootCmd.Flags().Bool("empty", false, "List only empty files & directories")
+	RootCmd.Flags().String("perm", "", "List only entries with exactly the octal permission, all of its bits with -mode or any with /mode")
+	RootCmd.Flags().String("owner", "", "List only entries owned by the user, name or UID")
+	RootCmd.Flags().String("owner-group", "", "List only entries owned by the group, name or GID")
 
 	//Color flag
 	RootCmd.Flags().String("dircolor", "gray", "Directory Color(gray/b, green/b, blue/b, brown/b, red/b, black/b, magenta/b, cyan/b)")
 	RootCmd.Flags().String("filecolor", "green", "File Color(gray/b, green/b, blue/b, brown/b, red/b, black/b, magenta/b, cyan/b)")
 	RootCmd.Flags().String("symlinkcolor", "blue", "SymLink Color(gray/b, green/b, blue/b, brown/b, red/b, black/b, magenta/b, cyan/b)")
+	RootCmd.Flags().String("execcolor", "greenb", "Executable Color(gray/b, green/b, blue/b, brown/b, red/b, black/b, magenta/b, cyan/b)")
+	RootCmd.Flags().String("fifocolor", "brown", "Named Pipe Color(gray/b, green/b, blue/b, brown/b, red/b, black/b, magenta/b, cyan/b)")
+	RootCmd.Flags().String("socketcolor", "magenta", "Socket Color(gray/b, green/b, blue/b, brown/b, red/b, black/b, magenta/b, cyan/b)")
+	RootCmd.Flags().String("devicecolor", "brownb", "Device Color(gray/b, green/b, blue/b, brown/b, red/b, black/b, magenta/b, cyan/b)")
 	RootCmd.Flags().String("tlinkcolor", "brown", "TLink Color(gray/b, green/b, blue/b, brown/b, red/b, black/b, magenta/b, cyan/b)")
 	RootCmd.Flags().String("llinkcolor", "brown", "Pipe Color(gray/b, green/b, blue/b, brown/b, red/b, black/b, magenta/b, cyan/b)")
+	RootCmd.Flags().String("errorcolor", "red", "Error Color(gray/b, green/b, blue/b, brown/b, red/b, black/b, magenta/b, cyan/b)")
 	RootCmd.Flags().String("pipecolor", "brown", "Pipe Color(gray/b, green/b, blue/b, brown/b, red/b, black/b, magenta/b, cyan/b)")
 
 	//Bind viper
 	viper.BindPFlag("filelimit", RootCmd.Flags().Lookup("filelimit"))
+	viper.BindPFlag("jobs", RootCmd.Flags().Lookup("jobs"))
+	viper.BindPFlag("hash", RootCmd.Flags().Lookup("hash"))
+	viper.BindPFlag("hashmaxsize", RootCmd.Flags().Lookup("hash-max-size"))
+	viper.BindPFlag("dupes", RootCmd.Flags().Lookup("dupes"))
 	viper.BindPFlag("timefmt", RootCmd.Flags().Lookup("timefmt"))
 	viper.BindPFlag("protection", RootCmd.Flags().Lookup("protection"))
 	viper.BindPFlag("size", RootCmd.Flags().Lookup("size"))
+	viper.BindPFlag("du", RootCmd.Flags().Lookup("du"))
+	viper.BindPFlag("blocks", RootCmd.Flags().Lookup("blocks"))
+	viper.BindPFlag("human", RootCmd.Flags().Lookup("human"))
+	viper.BindPFlag("si", RootCmd.Flags().Lookup("si"))
 	viper.BindPFlag("user", RootCmd.Flags().Lookup("user"))
 	viper.BindPFlag("group", RootCmd.Flags().Lookup("group"))
+	viper.BindPFlag("numericids", RootCmd.Flags().Lookup("numeric-ids"))
 	viper.BindPFlag("modtime", RootCmd.Flags().Lookup("modtime"))
+	viper.BindPFlag("ctime", RootCmd.Flags().Lookup("ctime"))
+	viper.BindPFlag("atime", RootCmd.Flags().Lookup("atime"))
+	viper.BindPFlag("inodes", RootCmd.Flags().Lookup("inodes"))
+	viper.BindPFlag("device", RootCmd.Flags().Lookup("device"))
+	viper.BindPFlag("nlink", RootCmd.Flags().Lookup("nlink"))
 	viper.BindPFlag("reverse", RootCmd.Flags().Lookup("reverse"))
 	viper.BindPFlag("sortbymodtime", RootCmd.Flags().Lookup("sortbymodtime"))
+	viper.BindPFlag("sort", RootCmd.Flags().Lookup("sort"))
+	viper.BindPFlag("dirsfirst", RootCmd.Flags().Lookup("dirsfirst"))
+	viper.BindPFlag("filesfirst", RootCmd.Flags().Lookup("filesfirst"))
+
+	viper.BindPFlag("minsize", RootCmd.Flags().Lookup("min-size"))
+	viper.BindPFlag("maxsize", RootCmd.Flags().Lookup("max-size"))
+	viper.BindPFlag("newer", RootCmd.Flags().Lookup("newer"))
+	viper.BindPFlag("older", RootCmd.Flags().Lookup("older"))
+	viper.BindPFlag("type", RootCmd.Flags().Lookup("type"))
+	viper.BindPFlag("empty", RootCmd.Flags().Lookup("empty"))
+	viper.BindPFlag("perm", RootCmd.Flags().Lookup("perm"))
+	viper.BindPFlag("owner", RootCmd.Flags().Lookup("owner"))
+	viper.BindPFlag("ownergroup", RootCmd.Flags().Lookup("owner-group"))
 
 	viper.BindPFlag("dironly", RootCmd.Flags().Lookup("dironly"))
 	viper.BindPFlag("output", RootCmd.Flags().Lookup("output"))
+	viper.BindPFlag("columns", RootCmd.Flags().Lookup("columns"))
+	viper.BindPFlag("mdlist", RootCmd.Flags().Lookup("mdlist"))
+	viper.BindPFlag("mdlinks", RootCmd.Flags().Lookup("mdlinks"))
+	viper.BindPFlag("baseurl", RootCmd.Flags().Lookup("baseurl"))
+	viper.BindPFlag("title", RootCmd.Flags().Lookup("title"))
 	viper.BindPFlag("nocolor", RootCmd.Flags().Lookup("nocolor"))
+	viper.BindPFlag("charset", RootCmd.Flags().Lookup("charset"))
 	viper.BindPFlag("all", RootCmd.Flags().Lookup("all"))
 	viper.BindPFlag("fullpath", RootCmd.Flags().Lookup("fullpath"))
+	viper.BindPFlag("classify", RootCmd.Flags().Lookup("classify"))
 	viper.BindPFlag("noreport", RootCmd.Flags().Lookup("noreport"))
 	viper.BindPFlag("followlink", RootCmd.Flags().Lookup("followlink"))
 	viper.BindPFlag("level", RootCmd.Flags().Lookup("level"))
 	viper.BindPFlag("includepattern", RootCmd.Flags().Lookup("includepattern"))
 	viper.BindPFlag("excludepattern", RootCmd.Flags().Lookup("excludepattern"))
+	viper.BindPFlag("regex", RootCmd.Flags().Lookup("regex"))
+	viper.BindPFlag("matchdirs", RootCmd.Flags().Lookup("matchdirs"))
+	viper.BindPFlag("gitignore", RootCmd.Flags().Lookup("gitignore"))
 	viper.BindPFlag("jsonindent", RootCmd.Flags().Lookup("jsonindent"))
 	viper.BindPFlag("includestats", RootCmd.Flags().Lookup("includestats"))
 
@@ -227,9 +508,14 @@ func init() {
 	viper.BindPFlag("dircolor", RootCmd.Flags().Lookup("dircolor"))
 	viper.BindPFlag("filecolor", RootCmd.Flags().Lookup("filecolor"))
 	viper.BindPFlag("symlinkcolor", RootCmd.Flags().Lookup("symlinkcolor"))
+	viper.BindPFlag("execcolor", RootCmd.Flags().Lookup("execcolor"))
+	viper.BindPFlag("fifocolor", RootCmd.Flags().Lookup("fifocolor"))
+	viper.BindPFlag("socketcolor", RootCmd.Flags().Lookup("socketcolor"))
+	viper.BindPFlag("devicecolor", RootCmd.Flags().Lookup("devicecolor"))
 	viper.BindPFlag("tlinkcolor", RootCmd.Flags().Lookup("tlinkcolor"))
 	viper.BindPFlag("llinkcolor", RootCmd.Flags().Lookup("llinkcolor"))
 	viper.BindPFlag("pipecolor", RootCmd.Flags().Lookup("pipecolor"))
+	viper.BindPFlag("errorcolor", RootCmd.Flags().Lookup("errorcolor"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -56,8 +56,8 @@ func ExampleHiTree_dirOnly() {
 	// └──a
 	//    ├──b
 	//    └──c
-	// │     └──d
-	// │  │     └──e
+	//       └──d
+	//          └──e
 	//
 	// 5 directories, 5 files
 }
@@ -157,7 +157,7 @@ func ExampleHiTree_includePattern() {
 	// │  │  └──normal.go
 	// │  └──c
 	// │     ├──d
-	// │  │  │  └──e
+	// │     │  └──e
 	// │     └──normal.go
 	// └──normal.go
 	//
@@ -180,6 +180,23 @@ func ExampleHiTree_patternWithDepth() {
 	// 3 directories, 1 files
 }
 
+// Print entries while the directory is being read
+func ExampleHiTree_stream() {
+	cleaner, _, root := helper.SetupTestDir("RootH")
+	defer cleaner()
+	// $ hitree root --stream --dironly
+	execute("hitree", root, "--stream", "--dironly")
+	// Output:
+	// RootH
+	// └──a
+	//    ├──b
+	//    └──c
+	//       └──d
+	//          └──e
+	//
+	// 5 directories, 5 files
+}
+
 func execute(command, root string, args ...string) {
 	args = append([]string{root, "--nocolor"}, args...)
 	path := fmt.Sprintf("PATH=%s:%s", os.Getenv("PATH"), os.Getenv("GOPATH"))
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Charset Characters used to draw the branches of the tree. Pipe is followed
+// by two spaces to line up with Tee & Last.
+type Charset struct {
+	Pipe string
+	Tee  string
+	Last string
+}
+
+// Charsets Supported charsets, which are also used in --charset flag
+var Charsets = map[string]Charset{
+	"utf8":  {Pipe: "│", Tee: "├──", Last: "└──"},
+	"ascii": {Pipe: "|", Tee: "|--", Last: "`--"},
+}
+
+// charset Charset selected in options, utf8 by default
+func (opt Options) charset() Charset {
+	if cs, ok := Charsets[opt.Charset]; ok {
+		return cs
+	}
+	return Charsets["utf8"]
+}
+
+// ValidateCharset Check that name is one of Charsets, empty selects utf8
+func ValidateCharset(name string) error {
+	if _, ok := Charsets[name]; ok || name == "" {
+		return nil
+	}
+	names := make([]string, 0, len(Charsets))
+	for n := range Charsets {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("invalid charset %q, possible charsets are %s", name, strings.Join(names, ", "))
+}
@@ -1,17 +1,25 @@
 package core
 
-import "github.com/logrusorgru/aurora"
+import (
+	"fmt"
+
+	"github.com/logrusorgru/aurora"
+)
 
 //Colorize A function type which is being used in Options
 type Colorize func(interface{}) aurora.Value
 
 var au aurora.Aurora
 
+// colorOutput whether colors are enabled, as set by InitAurora
+var colorOutput bool
+
 // InitAurora Utility method to initialize Aurora(ANSI color library),
 // being used to print colorize output.
 // Aurora Provides flag to allow enabling or disabling output
 func InitAurora(enableColorOutput bool) {
 	au = aurora.NewAurora(enableColorOutput)
+	colorOutput = enableColorOutput
 }
 
 // colorCurry: A partial function based on color and bold, which then
@@ -64,3 +72,31 @@ var ColorMap = map[string]Colorize{
 	"cyan":  colorCurry(aurora.CyanFg, false),
 	"cyanb": colorCurry(aurora.CyanFg, true),
 }
+
+// plainColor Colorize which never adds color, used by output formats which
+// must not contain ANSI escape sequences
+var plainColor Colorize = func(message interface{}) aurora.Value {
+	return aurora.NewAurora(false).Colorize(message, 0)
+}
+
+// withoutColors Returns opt with every color replaced by plainColor
+func (opt Options) withoutColors() Options {
+	opt.DirColor, opt.FileColor, opt.SymLinkColor = plainColor, plainColor, plainColor
+	opt.TLinkColor, opt.LLinkColor, opt.PipeColor = plainColor, plainColor, plainColor
+	opt.ErrorColor, opt.ExecColor, opt.FIFOColor = plainColor, plainColor, plainColor
+	opt.SocketColor, opt.DeviceColor = plainColor, plainColor
+	opt.LSColors = nil
+	return opt
+}
+
+// sgrColor Colorize which wraps the message in an arbitrary SGR sequence, like
+// the ones of LS_COLORS (e.g. 38;5;208), which aurora can't express. Colors
+// are only added when enabled in InitAurora, empty messages are left as is.
+func sgrColor(sgr string) Colorize {
+	return func(message interface{}) aurora.Value {
+		if !colorOutput || sgr == "" || fmt.Sprint(message) == "" {
+			return plainColor(message)
+		}
+		return plainColor(fmt.Sprintf("\033[%sm%v\033[0m", sgr, message))
+	}
+}
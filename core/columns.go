@@ -0,0 +1,140 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// extraDetail A detail of the node printed beside its name when enabled in
+// options, numbers are right aligned like in ls -l
+type extraDetail struct {
+	header  string
+	numeric bool
+	enabled func(opt Options) bool
+	value   func(stats Stats, opt Options) string
+}
+
+// extraDetails Details in the order they are printed
+var extraDetails = []extraDetail{
+	{"inode", true, func(opt Options) bool { return opt.PrintInode }, func(stats Stats, opt Options) string {
+		return identityField(stats, stats.Inode)
+	}},
+	{"device", true, func(opt Options) bool { return opt.PrintDevice }, func(stats Stats, opt Options) string {
+		return identityField(stats, stats.Device)
+	}},
+	{"links", true, func(opt Options) bool { return opt.PrintLinks }, func(stats Stats, opt Options) string {
+		return identityField(stats, stats.Links)
+	}},
+	{"user", false, func(opt Options) bool { return opt.PrintUID }, ownerOf},
+	{"group", false, func(opt Options) bool { return opt.PrintGID }, groupOf},
+	{"size", true, func(opt Options) bool { return opt.PrintSize }, func(stats Stats, opt Options) string {
+		return formatSize(sizeOf(stats, opt), opt)
+	}},
+	{"modified", false, func(opt Options) bool { return opt.PrintModTime }, func(stats Stats, opt Options) string {
+		return stats.ModificationTime.Format(opt.TimeFormat)
+	}},
+	{"changed", false, func(opt Options) bool { return opt.PrintChangeTime }, func(stats Stats, opt Options) string {
+		return stats.ChangeTime.Format(opt.TimeFormat)
+	}},
+	{"accessed", false, func(opt Options) bool { return opt.PrintAccessTime }, func(stats Stats, opt Options) string {
+		return stats.AccessTime.Format(opt.TimeFormat)
+	}},
+	{"permission", false, func(opt Options) bool { return opt.PrintProtection }, modeOf},
+	{"digest", false, func(opt Options) bool { return opt.Hash != "" }, func(stats Stats, opt Options) string {
+		if stats.Digest == "" {
+			return "-"
+		}
+		return stats.Digest
+	}},
+}
+
+// modeOf Full mode string of the node, snapshots exported before the mode was
+// recorded only have the permission
+func modeOf(stats Stats, opt Options) string {
+	if stats.Mode == "" {
+		return stats.Permission
+	}
+	return stats.Mode
+}
+
+// identityField Inode, device or link count, "-" when the node is not backed
+// by the file system, which is told by the link count every file has
+func identityField(stats Stats, value uint64) string {
+	if stats.Links == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", value)
+}
+
+//extraHeaders Helper private method to get the names of the extra details
+//returned by extraColumns
+func extraHeaders(opt Options) []string {
+	headers := make([]string, 0)
+	for _, d := range extraDetails {
+		if d.enabled(opt) {
+			headers = append(headers, d.header)
+		}
+	}
+	return headers
+}
+
+//extraColumns Helper private method to get the extra details of the node
+//enabled in options, in the order they are printed
+func extraColumns(tree Tree, opt Options) []string {
+	extra := make([]string, 0)
+	for _, d := range extraDetails {
+		if d.enabled(opt) {
+			extra = append(extra, d.value(tree.Stats, opt))
+		}
+	}
+	return extra
+}
+
+//columnWidths Helper private method to compute the width of every extra
+//column over the nodes of the tree which are printed
+func columnWidths(tree Tree, opt Options) []int {
+	widths := make([]int, len(extraHeaders(opt)))
+	if len(widths) == 0 {
+		return nil
+	}
+	var measure func(tree Tree)
+	measure = func(tree Tree) {
+		for i, value := range extraColumns(tree, opt) {
+			if n := len([]rune(value)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+		for _, subtree := range tree.Childrens {
+			if !canPrune(subtree, opt) {
+				measure(subtree)
+			}
+		}
+	}
+	measure(tree)
+	return widths
+}
+
+//alignColumns Helper private method to pad the extra columns to the widths
+//computed over the tree, numbers on the left & text on the right. Columns are
+//left as is when widths are not known, e.g. while streaming.
+func alignColumns(extra []string, opt Options) []string {
+	if len(opt.widths) != len(extra) {
+		return extra
+	}
+	numeric := make([]bool, 0, len(extra))
+	for _, d := range extraDetails {
+		if d.enabled(opt) {
+			numeric = append(numeric, d.numeric)
+		}
+	}
+	aligned := make([]string, len(extra))
+	for i, value := range extra {
+		pad := strings.Repeat(" ", opt.widths[i]-len([]rune(value)))
+		if numeric[i] {
+			aligned[i] = pad + value
+		} else {
+			aligned[i] = value + pad
+		}
+	}
+	return aligned
+}
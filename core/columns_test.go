@@ -0,0 +1,92 @@
+package core_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestModeString(t *testing.T) {
+	modes := map[os.FileMode]string{
+		0644:                                     "-rw-r--r--",
+		os.ModeDir | 0755:                        "drwxr-xr-x",
+		os.ModeSymlink | 0777:                    "lrwxrwxrwx",
+		os.ModeNamedPipe | 0600:                  "prw-------",
+		os.ModeSocket | 0755:                     "srwxr-xr-x",
+		os.ModeDevice | os.ModeCharDevice | 0666: "crw-rw-rw-",
+		os.ModeDevice | 0660:                     "brw-rw----",
+		os.ModeSetuid | 0755:                     "-rwsr-xr-x",
+		os.ModeSetuid | 0644:                     "-rwSr--r--",
+		os.ModeDir | os.ModeSetgid | 0755:        "drwxr-sr-x",
+		os.ModeDir | os.ModeSticky | 0777:        "drwxrwxrwt",
+		os.ModeDir | os.ModeSticky | 0770:        "drwxrwx--T",
+	}
+	for mode, expected := range modes {
+		if got := core.ModeString(mode); got != expected {
+			t.Errorf("Expected %s for %o, got %s", expected, uint32(mode), got)
+		}
+	}
+}
+
+func TestTreePrint_alignedColumns(t *testing.T) {
+	node := func(name string, mode os.FileMode, size int64, childrens ...core.Tree) core.Tree {
+		fi := core.VirtualFileInfo{FName: name, FMode: mode, FSize: size}
+		return core.Tree{Root: fi, Stats: core.NewEmptyStats(fi), Childrens: childrens}
+	}
+	tree := node("root", os.ModeDir|0755, 4096,
+		node("big", 0644, 123456),
+		node("small", os.ModeSetuid|0755, 7),
+	)
+	core.InitAurora(false)
+	opt := core.DefaultOptions()
+	opt.NoReport = true
+	opt.PrintSize, opt.PrintProtection, opt.PrintLinks = true, true, true
+	buf := new(bytes.Buffer)
+	tree.Print(buf, opt)
+	expected := `[ -   4096 drwxr-xr-x ]root
+├──[ - 123456 -rw-r--r-- ]big
+└──[ -      7 -rwsr-xr-x ]small
+`
+	if buf.String() != expected {
+		t.Errorf("Expected columns aligned over the tree\n%s\ngot\n%s", expected, buf.String())
+	}
+}
+
+func TestGetExtra_identity(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Inode & link count are not available on windows")
+	}
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	file := filepath.Join(root, "normal.go")
+	if err := os.Link(file, filepath.Join(root, "hardlink.go")); err != nil {
+		t.Skipf("Unable to create hard link, %v", err)
+	}
+	opt.MaxLevel = 0
+	tree, err := core.TraverseDir(file, opt, 0)
+	if err != nil {
+		t.Fatalf("Unable to traverse the file, %v", err)
+	}
+	if tree.Stats.Links != 2 || tree.Stats.Inode == 0 {
+		t.Errorf("Expected inode & 2 links, got %+v", tree.Stats)
+	}
+	other, _ := core.TraverseDir(filepath.Join(root, "hardlink.go"), opt, 0)
+	if other.Stats.Inode != tree.Stats.Inode || other.Stats.Device != tree.Stats.Device {
+		t.Errorf("Expected hard links to share inode & device, got %+v & %+v", tree.Stats, other.Stats)
+	}
+	if tree.Stats.ChangeTime.IsZero() || !tree.Stats.AccessTime.IsZero() {
+		t.Errorf("Expected change time only, got %+v", tree.Stats)
+	}
+	// Access time is only collected when printed
+	opt.PrintAccessTime = true
+	tree, _ = core.TraverseDir(file, opt, 0)
+	if tree.Stats.AccessTime.IsZero() {
+		t.Errorf("Expected access time, got %+v", tree.Stats)
+	}
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableColumns Columns of the CSV & TSV output, which are also used in
+// --columns flag
+var TableColumns = []string{"path", "name", "depth", "type", "size", "mode", "uid", "gid", "mtime", "digest", "error"}
+
+// tableWriter Writes one row per node, comma or tab separated
+type tableWriter struct {
+	w       *csv.Writer
+	columns []string
+	opt     Options
+}
+
+// newTableWriter Helper private method to validate the columns of the
+// options and write the header row. Empty opt.Columns selects all the columns.
+func newTableWriter(w io.Writer, opt Options, sep rune) (*tableWriter, error) {
+	columns := opt.Columns
+	if len(columns) == 0 {
+		columns = TableColumns
+	}
+	if err := ValidateTableColumns(columns); err != nil {
+		return nil, err
+	}
+	tw := &tableWriter{w: csv.NewWriter(w), columns: columns, opt: opt}
+	tw.w.Comma = sep
+	if err := tw.w.Write(columns); err != nil {
+		return nil, err
+	}
+	return tw, nil
+}
+
+// ValidateTableColumns Check that every column is one of TableColumns
+func ValidateTableColumns(columns []string) error {
+	for _, column := range columns {
+		valid := false
+		for _, known := range TableColumns {
+			valid = valid || column == known
+		}
+		if !valid {
+			return fmt.Errorf("invalid column %q, possible columns are %s", column, strings.Join(TableColumns, ", "))
+		}
+	}
+	return nil
+}
+
+func (tw *tableWriter) write(record nodeRecord) error {
+	row := make([]string, len(tw.columns))
+	for i, column := range tw.columns {
+		switch column {
+		case "path":
+			row[i] = record.path
+		case "name":
+			row[i] = record.fi.Name()
+		case "depth":
+			row[i] = strconv.Itoa(record.depth)
+		case "type":
+			row[i] = GetFileType(record.fi).String()
+		case "size":
+			row[i] = strconv.FormatInt(sizeOf(record.stats, tw.opt), 10)
+		case "mode":
+			row[i] = modeOf(record.stats, tw.opt)
+		case "uid":
+			row[i] = record.stats.UID
+		case "gid":
+			row[i] = record.stats.GID
+		case "mtime":
+			row[i] = record.stats.ModificationTime.Format(time.RFC3339)
+		case "digest":
+			row[i] = record.stats.Digest
+		case "error":
+			if record.err != nil {
+				row[i] = errorReason(record.err)
+			}
+		}
+	}
+	return tw.w.Write(row)
+}
+
+func (tw *tableWriter) flush() error {
+	tw.w.Flush()
+	return tw.w.Error()
+}
+
+// StreamTable walks the directory rooted at root and writes a header followed
+// by one row per node to w as soon as it is discovered, separated by sep (',' for
+// CSV or '\t' for TSV). Sizes are raw bytes and times are RFC 3339 so that the
+// output loads as is in spreadsheets & databases. Options are handled like by
+// StreamTree.
+func StreamTable(w io.Writer, root string, opt Options, sep rune) (Stats, error) {
+	tw, err := newTableWriter(w, opt, sep)
+	if err != nil {
+		return Stats{}, err
+	}
+	stats, err := streamRecords(root, opt, tw.write)
+	if err != nil {
+		return stats, err
+	}
+	return stats, tw.flush()
+}
+
+// WriteTable Write a header followed by one row per node of the tree to w, in
+// the order they are printed, separated by sep. Paths are built from the name of
+// the root.
+func (tree Tree) WriteTable(w io.Writer, opt Options, sep rune) error {
+	tw, err := newTableWriter(w, opt, sep)
+	if err != nil {
+		return err
+	}
+	if err := tree.walkRecords(opt, tw.write); err != nil {
+		return err
+	}
+	return tw.flush()
+}
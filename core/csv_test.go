@@ -0,0 +1,83 @@
+package core_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestStreamTable(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	opt.MaxLevel = 2
+	opt.Columns = []string{"path", "depth", "type"}
+	buf := new(bytes.Buffer)
+	stats, err := core.StreamTable(buf, root, opt, '\t')
+	if err != nil {
+		t.Errorf("Unable to stream the Dir, %v", err)
+	}
+	if stats.DirCount != 3 || stats.FileCount != 2 {
+		t.Errorf("Expected 3 directories & 2 files, got %+v", stats)
+	}
+	r := csv.NewReader(buf)
+	r.Comma = '\t'
+	rows, err := r.ReadAll()
+	if err != nil {
+		t.Errorf("Invalid TSV output, %v", err)
+	}
+	expected := [][]string{
+		{"path", "depth", "type"},
+		{root_file, "0", "dir"},
+		{root_file + "/a", "1", "dir"},
+		{root_file + "/a/b", "2", "dir"},
+		{root_file + "/a/c", "2", "dir"},
+		{root_file + "/a/normal.py", "2", "file"},
+		{root_file + "/normal.go", "1", "file"},
+	}
+	if fmt.Sprint(rows) != fmt.Sprint(expected) {
+		t.Errorf("Expected %q, got %q", expected, rows)
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	opt.MaxLevel = 1
+	tree, _ := core.TraverseDir(root, opt, 0)
+	opt.Columns = []string{"name", "size"}
+	buf := new(bytes.Buffer)
+	if err := tree.WriteTable(buf, opt, ','); err != nil {
+		t.Errorf("Unable to write the tree, %v", err)
+	}
+	rows, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Errorf("Invalid CSV output, %v", err)
+	}
+	if len(rows) != 4 || fmt.Sprint(rows[0]) != "[name size]" || rows[3][0] != "normal.go" || rows[3][1] != "0" {
+		t.Errorf("Unexpected rows %q", rows)
+	}
+
+	opt.Columns = []string{"name", "owner"}
+	if err := tree.WriteTable(buf, opt, ','); err == nil {
+		t.Errorf("Expected error for unknown column")
+	}
+
+	// Mode is the one of the stats, like in the permission column
+	os.Chmod(filepath.Join(root, "normal.go"), 0755|os.ModeSetuid)
+	tree, _ = core.TraverseDir(filepath.Join(root, "normal.go"), opt, 0)
+	opt.Columns = []string{"name", "mode"}
+	buf.Reset()
+	tree.WriteTable(buf, opt, ',')
+	if expected := "name,mode\nnormal.go,-rwsr-xr-x\n"; buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
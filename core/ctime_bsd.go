@@ -0,0 +1,26 @@
+//go:build darwin || freebsd || netbsd
+// +build darwin freebsd netbsd
+
+package core
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// changeTime Time of the last status change of fi
+func changeTime(fi os.FileInfo) time.Time {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(st.Ctimespec.Unix())
+	}
+	return fi.ModTime()
+}
+
+// accessTime Time of the last access of fi
+func accessTime(fi os.FileInfo) time.Time {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(st.Atimespec.Unix())
+	}
+	return fi.ModTime()
+}
@@ -0,0 +1,21 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!dragonfly
+
+package core
+
+import (
+	"os"
+	"time"
+)
+
+// changeTime Status change time is not available on this platform, so
+// modification time is used instead
+func changeTime(fi os.FileInfo) time.Time {
+	return fi.ModTime()
+}
+
+// accessTime Access time is not available on this platform, so modification
+// time is used instead
+func accessTime(fi os.FileInfo) time.Time {
+	return fi.ModTime()
+}
@@ -0,0 +1,255 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+//ChangeKind EnumType for the change of an entry between two trees
+type ChangeKind int
+
+// Enum for ChangeKind
+const (
+	UNCHANGED ChangeKind = iota
+	ADDED
+	REMOVED
+	MODIFIED
+)
+
+// changeMarkers Marker printed before the name of the changed entry
+var changeMarkers = map[ChangeKind]string{
+	UNCHANGED: " ",
+	ADDED:     "+",
+	REMOVED:   "-",
+	MODIFIED:  "~",
+}
+
+// DiffAttributes Attributes compared for entries present in both trees, which
+// are also used in --compare flag. Attributes are only compared when both
+// trees carry stats i.e. JSON snapshots exported with --includestats. Digest
+// is only compared when both trees were hashed with the same algorithm.
+var DiffAttributes = []string{"size", "mtime", "perm", "digest"}
+
+// DiffOptions Options controlling comparison & rendering of the diff
+type DiffOptions struct {
+	// Compare subset of DiffAttributes to compare, empty compares all
+	Compare []string
+	// OnlyChanges omit unchanged entries which have no changes below them
+	OnlyChanges  bool
+	AddedColor   Colorize
+	RemovedColor Colorize
+	ChangedColor Colorize
+}
+
+// DiffTree Merged tree of two trees where every node records how it changed
+type DiffTree struct {
+	Name      string
+	FType     FileType
+	Change    ChangeKind
+	Reasons   []string
+	Childrens []DiffTree
+}
+
+// DiffStats Count of the entries by kind of change
+type DiffStats struct {
+	Added     int
+	Removed   int
+	Modified  int
+	Unchanged int
+}
+
+func (stats *DiffStats) count(change ChangeKind) {
+	switch change {
+	case ADDED:
+		stats.Added++
+	case REMOVED:
+		stats.Removed++
+	case MODIFIED:
+		stats.Modified++
+	default:
+		stats.Unchanged++
+	}
+}
+
+// DefaultDiffOptions Options comparing all the attributes, with added entries
+// in green, removed in red and modified in brown
+func DefaultDiffOptions() DiffOptions {
+	return DiffOptions{
+		AddedColor:   ColorMap["green"],
+		RemovedColor: ColorMap["red"],
+		ChangedColor: ColorMap["brown"],
+	}
+}
+
+// Diff Compare tree a against tree b and return the merged tree along with the
+// count of changes below the root. Children are matched by name.
+func Diff(a, b Tree, dopt DiffOptions) (DiffTree, DiffStats) {
+	var stats DiffStats
+	root := diffNode(&a, &b, dopt, &stats)
+	if an, bn := a.Root.Name(), b.Root.Name(); an != bn {
+		root.Name = fmt.Sprintf("%s -> %s", an, bn)
+	}
+	return root, stats
+}
+
+func diffNode(a, b *Tree, dopt DiffOptions, stats *DiffStats) DiffTree {
+	var node DiffTree
+	switch {
+	case a == nil:
+		node = DiffTree{Name: b.Root.Name(), FType: GetFileType(b.Root), Change: ADDED}
+	case b == nil:
+		node = DiffTree{Name: a.Root.Name(), FType: GetFileType(a.Root), Change: REMOVED}
+	default:
+		node = DiffTree{Name: b.Root.Name(), FType: GetFileType(b.Root)}
+		node.Reasons = compareNodes(*a, *b, dopt)
+		if len(node.Reasons) > 0 {
+			node.Change = MODIFIED
+		}
+	}
+
+	children := map[string][2]*Tree{}
+	names := make([]string, 0)
+	add := func(t *Tree, side int) {
+		if t == nil {
+			return
+		}
+		for i := range t.Childrens {
+			name := t.Childrens[i].Root.Name()
+			pair, ok := children[name]
+			if !ok {
+				names = append(names, name)
+			}
+			pair[side] = &t.Childrens[i]
+			children[name] = pair
+		}
+	}
+	add(a, 0)
+	add(b, 1)
+	sort.Strings(names)
+	for _, name := range names {
+		pair := children[name]
+		child := diffNode(pair[0], pair[1], dopt, stats)
+		stats.count(child.Change)
+		if dopt.OnlyChanges && !child.hasChanges() {
+			continue
+		}
+		node.Childrens = append(node.Childrens, child)
+	}
+	return node
+}
+
+// compareNodes Attributes which differ between two versions of the entry
+func compareNodes(a, b Tree, dopt DiffOptions) []string {
+	reasons := make([]string, 0)
+	if GetFileType(a.Root) != GetFileType(b.Root) {
+		return append(reasons, "type")
+	}
+	// Attributes are only known if both trees have stats
+	if !a.Stats.Present() || !b.Stats.Present() {
+		return reasons
+	}
+	compare := dopt.Compare
+	if len(compare) == 0 {
+		compare = DiffAttributes
+	}
+	for _, attr := range compare {
+		switch attr {
+		case "size":
+			// Size of directory depends on the file system, only content matters
+			if !a.Root.IsDir() && a.Stats.Size != b.Stats.Size {
+				reasons = append(reasons, attr)
+			}
+		case "mtime":
+			// Modification time of directory changes with its content
+			if !a.Root.IsDir() && !a.Stats.ModificationTime.Equal(b.Stats.ModificationTime) {
+				reasons = append(reasons, attr)
+			}
+		case "perm":
+			if a.Stats.Permission != b.Stats.Permission {
+				reasons = append(reasons, attr)
+			}
+		case "digest":
+			da, db := a.Stats.Digest, b.Stats.Digest
+			// Digests of different algorithms can't be compared. Directories
+			// only differ by digest if their content does.
+			sameAlgorithm := a.Stats.DigestAlgorithm == b.Stats.DigestAlgorithm
+			if !a.Root.IsDir() && da != "" && db != "" && sameAlgorithm && da != db {
+				reasons = append(reasons, attr)
+			}
+		}
+	}
+	return reasons
+}
+
+//hasChanges Check if the node or anything below it changed
+func (diff DiffTree) hasChanges() bool {
+	if diff.Change != UNCHANGED {
+		return true
+	}
+	for _, c := range diff.Childrens {
+		if c.hasChanges() {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateDiffAttributes Check that every attribute is one of DiffAttributes
+func ValidateDiffAttributes(attrs []string) error {
+	for _, attr := range attrs {
+		valid := false
+		for _, known := range DiffAttributes {
+			valid = valid || attr == known
+		}
+		if !valid {
+			return fmt.Errorf("invalid attribute %q to compare, possible attributes are %s", attr, strings.Join(DiffAttributes, ", "))
+		}
+	}
+	return nil
+}
+
+// Print Print the merged tree, marking added entries with +, removed with -
+// and modified with ~ followed by the attributes which changed, and then the
+// report of changes.
+func (diff DiffTree) Print(w io.Writer, stats DiffStats, opt Options, dopt DiffOptions) {
+	fmt.Fprintf(w, "%s\n", opt.DirColor(diff.Name))
+	diff.printChildrens(w, opt, dopt, "")
+	if !opt.NoReport {
+		fmt.Fprintf(w, "\n%d added, %d removed, %d modified, %d unchanged\n", stats.Added, stats.Removed, stats.Modified, stats.Unchanged)
+	}
+}
+
+func (diff DiffTree) printChildrens(w io.Writer, opt Options, dopt DiffOptions, prefix string) {
+	cs := opt.charset()
+	for i, child := range diff.Childrens {
+		branch, childPrefix := opt.TLinkColor(cs.Tee), fmt.Sprintf("%s%s  ", prefix, opt.PipeColor(cs.Pipe))
+		if i == len(diff.Childrens)-1 {
+			branch, childPrefix = opt.LLinkColor(cs.Last), prefix+"   "
+		}
+		fmt.Fprintf(w, "%s%s", prefix, branch)
+		child.printNode(w, opt, dopt)
+		child.printChildrens(w, opt, dopt, childPrefix)
+	}
+}
+
+func (diff DiffTree) printNode(w io.Writer, opt Options, dopt DiffOptions) {
+	colorize := opt.FileColor
+	if diff.FType == DIR {
+		colorize = opt.DirColor
+	}
+	switch diff.Change {
+	case ADDED:
+		colorize = dopt.AddedColor
+	case REMOVED:
+		colorize = dopt.RemovedColor
+	case MODIFIED:
+		colorize = dopt.ChangedColor
+	}
+	line := fmt.Sprintf("%s %s", changeMarkers[diff.Change], diff.Name)
+	if len(diff.Reasons) > 0 {
+		line = fmt.Sprintf("%s [ %s ]", line, strings.Join(diff.Reasons, " "))
+	}
+	fmt.Fprintf(w, "%s\n", colorize(line))
+}
@@ -0,0 +1,115 @@
+package core_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestDiff(t *testing.T) {
+	before, after := uuid.New().String(), uuid.New().String()
+	cleanerA, opt, rootA := helper.SetupTestDir(before)
+	defer cleanerA()
+	cleanerB, _, rootB := helper.SetupTestDir(after)
+	defer cleanerB()
+	os.RemoveAll(filepath.Join(rootB, "a", "c", "d"))
+	ioutil.WriteFile(filepath.Join(rootB, "a", "normal.py"), []byte("print()"), 0666)
+	os.Chmod(filepath.Join(rootB, "normal.go"), 0600)
+	os.MkdirAll(filepath.Join(rootB, "z"), 0777)
+	ioutil.WriteFile(filepath.Join(rootB, "z", "new.go"), nil, 0666)
+
+	a, _ := core.TraverseDir(rootA, opt, 0)
+	b, _ := core.TraverseDir(rootB, opt, 0)
+	dopt := core.DefaultDiffOptions()
+	dopt.Compare = []string{"size", "perm"}
+	diff, stats := core.Diff(a, b, dopt)
+	expectedStats := core.DiffStats{Added: 2, Removed: 3, Modified: 2, Unchanged: 5}
+	if stats != expectedStats {
+		t.Errorf("Expected %+v, got %+v", expectedStats, stats)
+	}
+
+	dopt.OnlyChanges = true
+	diff, _ = core.Diff(a, b, dopt)
+	buf := new(bytes.Buffer)
+	diff.Print(buf, stats, opt, dopt)
+	output := fmt.Sprintf(`%s -> %s
+├──  a
+│  ├──  c
+│  │  └──- d
+│  │     ├──- e
+│  │     └──- normal.py
+│  └──~ normal.py [ size ]
+├──~ normal.go [ perm ]
+└──+ z
+   └──+ new.go
+
+2 added, 3 removed, 2 modified, 5 unchanged
+`, before, after)
+	if buf.String() != output {
+		t.Errorf("expected %s, got: \n%s", output, buf.String())
+	}
+}
+
+func TestDiffAttributes(t *testing.T) {
+	if err := core.ValidateDiffAttributes([]string{"size", "mtime", "perm"}); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := core.ValidateDiffAttributes([]string{"owner"}); err == nil {
+		t.Errorf("Expected error for unknown attribute")
+	}
+}
+
+func TestDiff_snapshotWithoutStats(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	ioutil.WriteFile(filepath.Join(root, "normal.go"), []byte("package main\n"), 0666)
+	live, _ := core.TraverseDir(root, opt, 0)
+	snapshot, err := live.AsJSONString(opt)
+	if err != nil {
+		t.Fatalf("Unable to export the tree, %v", err)
+	}
+	loaded, err := core.LoadJSON(bytes.NewReader(snapshot))
+	if err != nil {
+		t.Fatalf("Unable to load the tree, %v", err)
+	}
+	// Only the entries are compared when one side has no stats
+	_, stats := core.Diff(loaded, live, core.DefaultDiffOptions())
+	if stats.Modified != 0 || stats.Added != 0 || stats.Removed != 0 {
+		t.Errorf("Expected no change against snapshot without stats, got %+v", stats)
+	}
+}
+
+func TestDiff_executable(t *testing.T) {
+	before, after := uuid.New().String(), uuid.New().String()
+	cleanerA, opt, rootA := helper.SetupTestDir(before)
+	defer cleanerA()
+	cleanerB, _, rootB := helper.SetupTestDir(after)
+	defer cleanerB()
+	os.Chmod(filepath.Join(rootA, "normal.go"), 0644)
+	os.Chmod(filepath.Join(rootB, "normal.go"), 0755)
+
+	a, _ := core.TraverseDir(rootA, opt, 0)
+	b, _ := core.TraverseDir(rootB, opt, 0)
+	dopt := core.DefaultDiffOptions()
+	dopt.Compare = []string{"perm"}
+	dopt.OnlyChanges = true
+	diff, stats := core.Diff(a, b, dopt)
+	buf := new(bytes.Buffer)
+	diff.Print(buf, stats, opt, dopt)
+	// Making a file executable changes its permission, not its type
+	output := fmt.Sprintf(`%s -> %s
+└──~ normal.go [ perm ]
+
+0 added, 0 removed, 1 modified, 9 unchanged
+`, before, after)
+	if buf.String() != output {
+		t.Errorf("expected %s, got: \n%s", output, buf.String())
+	}
+}
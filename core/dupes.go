@@ -0,0 +1,177 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// DupeSet Files with the same content, in the order they are listed
+type DupeSet struct {
+	Size   int64    `json:"size"`
+	Digest string   `json:"digest"`
+	Paths  []string `json:"paths"`
+}
+
+// Wasted Space taken by the copies beside the first file of the set
+func (set DupeSet) Wasted() int64 {
+	return set.Size * int64(len(set.Paths)-1)
+}
+
+// dupeGroup Nodes of the files with the same content, along with their paths
+type dupeGroup struct {
+	size   int64
+	digest string
+	nodes  []*Tree
+	paths  []string
+}
+
+// FindDuplicates Find the regular files of the tree rooted at root which have
+// the same content. Files are grouped by size first, so only the files which
+// share their size with another one are digested, using opt.Hash or sha256
+// when it is not set. Digests already computed by TraverseDir with the same
+// hash are reused. Empty files, hard links to a file already seen & files
+// which can not be read are left out. Sets are sorted by wasted space, the
+// largest first.
+func FindDuplicates(tree Tree, root string, opt Options) ([]DupeSet, error) {
+	if err := ValidateHash(opt.Hash); err != nil {
+		return nil, err
+	}
+	groups := duplicateGroups(&tree, root, opt)
+	sets := make([]DupeSet, len(groups))
+	for i, g := range groups {
+		sets[i] = DupeSet{Size: g.size, Digest: g.digest, Paths: g.paths}
+	}
+	return sets, nil
+}
+
+// duplicateGroups Helper private method to group the nodes of the duplicate
+// files of tree, which path is root
+func duplicateGroups(tree *Tree, root string, opt Options) []dupeGroup {
+	bySize := make(map[int64][]*hashJob)
+	sizes := make([]int64, 0)
+	seen := make(map[[2]uint64]bool)
+	var collect func(tree *Tree, p string)
+	collect = func(tree *Tree, p string) {
+		for i := range tree.Childrens {
+			collect(&tree.Childrens[i], filepath.Join(p, tree.Childrens[i].Root.Name()))
+		}
+		if GetFileType(tree.Root) != FILE || tree.Err != nil || tree.Root.Size() == 0 {
+			return
+		}
+		if tree.Stats.Links > 1 {
+			id := [2]uint64{tree.Stats.Device, tree.Stats.Inode}
+			if seen[id] {
+				return
+			}
+			seen[id] = true
+		}
+		size := tree.Root.Size()
+		if _, ok := bySize[size]; !ok {
+			sizes = append(sizes, size)
+		}
+		bySize[size] = append(bySize[size], &hashJob{node: tree, path: p, digest: tree.Stats.Digest})
+	}
+	collect(tree, root)
+
+	name := opt.Hash
+	if name == "" {
+		name = "sha256"
+	}
+	pending := make([]*hashJob, 0)
+	for _, size := range sizes {
+		if len(bySize[size]) < 2 {
+			continue
+		}
+		for _, job := range bySize[size] {
+			// Digests of an other hash can't be compared, compute them all
+			if job.digest == "" || opt.Hash == "" {
+				pending = append(pending, job)
+			}
+		}
+	}
+	digestFiles(pending, HashAlgorithms[name], hashWorkers(opt))
+
+	groups := make([]dupeGroup, 0)
+	for _, size := range sizes {
+		byDigest := make(map[string]*dupeGroup)
+		digests := make([]string, 0)
+		for _, job := range bySize[size] {
+			if len(bySize[size]) < 2 || job.err != nil || job.digest == "" {
+				continue
+			}
+			g, ok := byDigest[job.digest]
+			if !ok {
+				g = &dupeGroup{size: size, digest: job.digest}
+				byDigest[job.digest] = g
+				digests = append(digests, job.digest)
+			}
+			g.nodes, g.paths = append(g.nodes, job.node), append(g.paths, job.path)
+		}
+		for _, digest := range digests {
+			if g := byDigest[digest]; len(g.nodes) > 1 {
+				groups = append(groups, *g)
+			}
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		wi := groups[i].size * int64(len(groups[i].nodes)-1)
+		wj := groups[j].size * int64(len(groups[j].nodes)-1)
+		return wi > wj
+	})
+	return groups
+}
+
+// markDuplicates Helper private method to mark every file of the tree rooted
+// at root which has the same content as a file listed before it, with the
+// path of that file relative to root
+func markDuplicates(tree *Tree, root string, opt Options) {
+	for _, g := range duplicateGroups(tree, root, opt) {
+		original := g.paths[0]
+		if rel, err := filepath.Rel(root, original); err == nil {
+			original = filepath.ToSlash(rel)
+		}
+		for _, node := range g.nodes[1:] {
+			node.DuplicateOf = original
+		}
+	}
+}
+
+// PrintDuplicates Print every set of duplicates with the paths of its files,
+// followed by the report of the space they waste unless opt.NoReport is set
+func PrintDuplicates(w io.Writer, sets []DupeSet, opt Options) {
+	var wasted int64
+	for i, set := range sets {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%d files of %s, %s wasted\n", len(set.Paths), sizeLabel(set.Size, opt), sizeLabel(set.Wasted(), opt))
+		for _, p := range set.Paths {
+			fmt.Fprintf(w, "  %s\n", opt.FileColor(p))
+		}
+		wasted += set.Wasted()
+	}
+	if opt.NoReport {
+		return
+	}
+	if len(sets) > 0 {
+		fmt.Fprintln(w)
+	}
+	noun := "sets"
+	if len(sets) == 1 {
+		noun = "set"
+	}
+	fmt.Fprintf(w, "%d duplicate %s, %s wasted\n", len(sets), noun, sizeLabel(wasted, opt))
+}
+
+// sizeLabel Helper private method to format size, in bytes unless it is
+// printed in human readable units
+func sizeLabel(size int64, opt Options) string {
+	label := formatSize(size, opt)
+	if _, err := strconv.ParseInt(label, 10, 64); err == nil {
+		return label + " bytes"
+	}
+	return label
+}
@@ -0,0 +1,107 @@
+package core_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+// writeFiles Write the content of the files by their path relative to root
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Unable to write %s, %v", name, err)
+		}
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	writeFiles(t, root, map[string]string{
+		"normal.go":             "package main\n",
+		"a/b/normal.go":         "package main\n",
+		"a/c/normal.go":         "package main\n",
+		"a/normal.py":           "print('a')\n",
+		"a/c/normal.py":         "print('a')\n",
+		"a/c/d/normal.py":       "print('b')\n",
+		"a/c/d/e/same_size.txt": "package mine\n",
+	})
+	tree, err := core.TraverseDir(root, opt, -1)
+	if err != nil {
+		t.Fatalf("Unable to traverse the Dir, %v", err)
+	}
+	sets, err := core.FindDuplicates(tree, root, opt)
+	if err != nil {
+		t.Fatalf("Unable to find duplicates, %v", err)
+	}
+	if len(sets) != 2 {
+		t.Fatalf("Expected 2 sets of duplicates, got %+v", sets)
+	}
+	expected := []string{
+		filepath.Join(root, "a/b/normal.go"),
+		filepath.Join(root, "a/c/normal.go"),
+		filepath.Join(root, "normal.go"),
+	}
+	if !reflect.DeepEqual(sets[0].Paths, expected) || sets[0].Wasted() != 26 {
+		t.Errorf("Expected largest waste first %v, got %+v", expected, sets[0])
+	}
+	if len(sets[1].Paths) != 2 || sets[1].Wasted() != 11 {
+		t.Errorf("Expected python files to be duplicates, got %+v", sets[1])
+	}
+
+	buf := new(bytes.Buffer)
+	core.PrintDuplicates(buf, sets, opt)
+	if !strings.HasPrefix(buf.String(), "3 files of 13 bytes, 26 bytes wasted\n") ||
+		!strings.HasSuffix(buf.String(), "\n2 duplicate sets, 37 bytes wasted\n") {
+		t.Errorf("Unexpected output of duplicates\n%s", buf.String())
+	}
+
+	opt.Hash = "crc"
+	if _, err := core.FindDuplicates(tree, root, opt); err == nil {
+		t.Errorf("Expected invalid hash to be reported")
+	}
+}
+
+func TestTraverseDir_markDuplicates(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Hard links can't be told apart without inode on windows")
+	}
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	writeFiles(t, root, map[string]string{"normal.go": "package main\n", "a/b/normal.go": "package main\n"})
+	// Hard links share their content on disk, they don't waste space
+	if err := os.Link(filepath.Join(root, "normal.go"), filepath.Join(root, "zlink.go")); err != nil {
+		t.Skipf("Unable to create hard link, %v", err)
+	}
+	opt.MarkDuplicates = true
+	tree, err := core.TraverseDir(root, opt, -1)
+	if err != nil {
+		t.Fatalf("Unable to traverse the Dir, %v", err)
+	}
+	if dup := findChild(tree, "normal.go").DuplicateOf; dup != "a/b/normal.go" {
+		t.Errorf("Expected normal.go to be marked as duplicate of a/b/normal.go, got %q", dup)
+	}
+	if dup := findChild(tree, "zlink.go").DuplicateOf; dup != "" {
+		t.Errorf("Expected hard link not to be marked, got %q", dup)
+	}
+	data, _ := json.Marshal(tree.AsJSONTree(opt))
+	if !strings.Contains(string(data), `"duplicate_of":"a/b/normal.go"`) {
+		t.Errorf("Expected duplicate in JSON, got %s", data)
+	}
+	buf := new(bytes.Buffer)
+	tree.Print(buf, opt)
+	if !strings.Contains(buf.String(), "normal.go [duplicate of a/b/normal.go]\n") {
+		t.Errorf("Expected duplicate marked inline, got\n%s", buf.String())
+	}
+}
@@ -0,0 +1,9 @@
+package core
+
+// UnregisterRenderer Remove the renderer registered by name, so tests can
+// clean up the global registry
+func UnregisterRenderer(name string) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	delete(renderers, name)
+}
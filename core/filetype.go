@@ -11,22 +11,50 @@ type FileType int
 
 // Enum for FileType
 const (
-	FILE = 1
-	DIR  = 2
+	FILE     = 1
+	DIR      = 2
+	LINK     = 3
+	FIFO     = 4
+	SOCKET   = 5
+	CHARDEV  = 6
+	BLOCKDEV = 7
 )
 
 // FTypeName Map of fileType to Its String name
 var FTypeName = map[FileType]string{
-	FILE: "file",
-	DIR:  "dir",
+	FILE:     "file",
+	DIR:      "dir",
+	LINK:     "link",
+	FIFO:     "fifo",
+	SOCKET:   "socket",
+	CHARDEV:  "char",
+	BLOCKDEV: "block",
 }
 
 // FTypeIds Map of String Name to Its FileType
 var FTypeIds = map[string]FileType{
-	"file": FILE,
-	"dir":  DIR,
+	"file":   FILE,
+	"dir":    DIR,
+	"link":   LINK,
+	"fifo":   FIFO,
+	"socket": SOCKET,
+	"char":   CHARDEV,
+	"block":  BLOCKDEV,
 }
 
+// FTypeIndicator Map of fileType to the suffix appended by classify (-F)
+// like ls -F does, regular files & devices have none. Executables are
+// regular files marked with ExecIndicator.
+var FTypeIndicator = map[FileType]string{
+	DIR:    "/",
+	LINK:   "@",
+	FIFO:   "|",
+	SOCKET: "=",
+}
+
+// ExecIndicator Suffix appended by classify (-F) to executable files
+const ExecIndicator = "*"
+
 // String Implementation of Stringer interface
 func (ftype FileType) String() string {
 	return FTypeName[ftype]
@@ -56,8 +84,54 @@ func (ftype *FileType) UnmarshalJSON(b []byte) error {
 
 //GetFileType Utility method to get fileType
 func GetFileType(fi os.FileInfo) FileType {
-	if fi.IsDir() {
+	mode := fi.Mode()
+	switch {
+	case mode.IsDir():
 		return DIR
+	case mode&os.ModeSymlink != 0:
+		return LINK
+	case mode&os.ModeNamedPipe != 0:
+		return FIFO
+	case mode&os.ModeSocket != 0:
+		return SOCKET
+	case mode&os.ModeCharDevice != 0:
+		return CHARDEV
+	case mode&os.ModeDevice != 0:
+		return BLOCKDEV
 	}
 	return FILE
 }
+
+//IsExecutable Check if fi is a regular file executable by anyone. Being
+//executable only affects how the file is displayed, its type stays FILE.
+func IsExecutable(fi os.FileInfo) bool {
+	return fi.Mode().IsRegular() && fi.Mode()&0111 != 0
+}
+
+//typeIndicator Suffix appended by classify to the name of fi
+func typeIndicator(fi os.FileInfo) string {
+	if IsExecutable(fi) {
+		return ExecIndicator
+	}
+	return FTypeIndicator[GetFileType(fi)]
+}
+
+//FileModeOf Mode used for nodes of the given fileType which are not backed by
+//the file system
+func FileModeOf(ftype FileType) os.FileMode {
+	switch ftype {
+	case DIR:
+		return os.ModeDir | 0755
+	case LINK:
+		return os.ModeSymlink | 0777
+	case FIFO:
+		return os.ModeNamedPipe | 0644
+	case SOCKET:
+		return os.ModeSocket | 0755
+	case CHARDEV:
+		return os.ModeDevice | os.ModeCharDevice | 0644
+	case BLOCKDEV:
+		return os.ModeDevice | 0644
+	}
+	return 0644
+}
@@ -0,0 +1,86 @@
+package core_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestGetFileType(t *testing.T) {
+	modes := map[core.FileType]os.FileMode{
+		core.FILE:     0644,
+		core.DIR:      os.ModeDir | 0755,
+		core.LINK:     os.ModeSymlink | 0777,
+		core.FIFO:     os.ModeNamedPipe | 0644,
+		core.SOCKET:   os.ModeSocket | 0755,
+		core.CHARDEV:  os.ModeDevice | os.ModeCharDevice | 0644,
+		core.BLOCKDEV: os.ModeDevice | 0644,
+	}
+	for ftype, mode := range modes {
+		fi := core.VirtualFileInfo{FName: "x", FMode: mode}
+		if got := core.GetFileType(fi); got != ftype {
+			t.Errorf("Expected %s for mode %s, got %s", ftype, mode, got)
+		}
+		if got := core.GetFileType(core.VirtualFileInfo{FMode: core.FileModeOf(ftype)}); got != ftype {
+			t.Errorf("Expected mode of %s to have the same type, got %s", ftype, got)
+		}
+
+		data, err := json.Marshal(ftype)
+		if err != nil {
+			t.Errorf("Unable to marshal %s, %v", ftype, err)
+		}
+		var decoded core.FileType
+		if err := json.Unmarshal(data, &decoded); err != nil || decoded != ftype {
+			t.Errorf("Expected %s after round trip of %s, got %s %v", ftype, data, decoded, err)
+		}
+	}
+	// Executables are regular files
+	exec := core.VirtualFileInfo{FName: "x", FMode: 0755}
+	if got := core.GetFileType(exec); got != core.FILE || !core.IsExecutable(exec) {
+		t.Errorf("Expected executable file, got %s", got)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupFlattenTestDir(root_file)
+	defer cleaner()
+	ioutil.WriteFile(filepath.Join(root, "run.sh"), nil, 0755)
+	os.Chmod(filepath.Join(root, "run.sh"), 0755)
+	os.Symlink("a", filepath.Join(root, "link"))
+	opt.Classify = true
+	opt.NoReport = true
+	opt.IncludePattern = []string{"a", "link", "run.sh", "normal.go"}
+	tree, err := core.TraverseDir(root, opt, 0)
+	if err != nil {
+		t.Fatalf("Unable to traverse the Dir, %v", err)
+	}
+	buf := new(bytes.Buffer)
+	tree.Print(buf, opt)
+	output := fmt.Sprintf(`%s/
+├──a/
+├──link@ -> a
+├──normal.go
+└──run.sh*
+`, root_file)
+	if output != buf.String() {
+		t.Errorf("expected %s, got: \n%s", output, buf.String())
+	}
+
+	// Followed links are classified by their target
+	opt.FollowLink = true
+	tree, _ = core.TraverseDir(root, opt, 0)
+	buf.Reset()
+	tree.Print(buf, opt)
+	if !bytes.Contains(buf.Bytes(), []byte("├──link -> a/\n")) {
+		t.Errorf("expected link to be classified as directory, got: \n%s", buf.String())
+	}
+}
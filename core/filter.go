@@ -3,7 +3,6 @@ package core
 import (
 	"os"
 	"path"
-	"path/filepath"
 	"strings"
 )
 
@@ -42,23 +41,21 @@ func FilterOutHidden(fis []os.FileInfo) []os.FileInfo {
 	return Filter(fis, isVisible)
 }
 
-// FileFilterPattern Filter fileInfos whose filepath matched with patern. Include flag
+// FileFilterPattern Filter fileInfos whose name matched with patern. Include flag
 // is used to control the inclusion/exclusion of matched fileinfos in the result set.
-// eg. If include flag is true, and file path matched with given pattern, then that
+// eg. If include flag is true, and file name matched with given pattern, then that
 // file info will be incuded in the result.
+// Malformed pattern is reported as error, refer https://golang.org/pkg/path/filepath/#Match
 // Note: This filter will only be applied on files
-func FileFilterPattern(fis []os.FileInfo, pattern string, include bool) []os.FileInfo {
+func FileFilterPattern(fis []os.FileInfo, pattern string, include bool) ([]os.FileInfo, error) {
+	set, err := NewPatternSet([]string{pattern}, false)
+	if err != nil {
+		return nil, err
+	}
 	return Filter(fis, func(fi os.FileInfo) bool {
 		if fi.IsDir() {
 			return true
 		}
-		matched, err := filepath.Match(pattern, fi.Name())
-		if err != nil {
-			panic("\nRegularExpression is incorrect, Please refer https://golang.org/pkg/path/filepath/#Match\n")
-		}
-		if include {
-			return matched
-		}
-		return !matched
-	})
+		return set.Match(fi.Name()) == include
+	}), nil
 }
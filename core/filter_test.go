@@ -79,9 +79,24 @@ func TestFileFilterPattern(t *testing.T) {
 	}
 
 	for _, test := range table {
-		filterFis := core.FileFilterPattern(fis, test.pattern, test.include) // Directory a will not be filtered out
+		filterFis, err := core.FileFilterPattern(fis, test.pattern, test.include) // Directory a will not be filtered out
+		if err != nil {
+			t.Errorf("Unexpected error for pattern %s, %v", test.pattern, err)
+		}
 		if len(filterFis) != test.expeted {
 			t.Errorf("There should have been only %d file infos, but got %d", test.expeted, len(filterFis))
 		}
 	}
 }
+
+func TestFileFilterPatternMalformed(t *testing.T) {
+	cleaner, _, root := helper.SetupFlattenTestDir(uuid.New().String())
+	defer cleaner()
+	fis, err := ioutil.ReadDir(root)
+	if err != nil {
+		t.Errorf("Unable to read files from %s, %v", root, err)
+	}
+	if _, err := core.FileFilterPattern(fis, "[*.go", true); err == nil {
+		t.Errorf("Expected error for malformed pattern")
+	}
+}
@@ -0,0 +1,279 @@
+package core
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// ignoreRule A single compiled line of a gitignore file
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreFile Rules of one gitignore file along with the directory they are
+// relative to
+type ignoreFile struct {
+	base  string
+	rules []ignoreRule
+}
+
+// GitIgnore Matcher implementing gitignore semantics for a traversal. Rules are
+// collected from the global excludes file, .git/info/exclude of the enclosing
+// repository and every .gitignore between the repository and the visited
+// directory. .gitignore files inside the traversal are loaded lazily and cached,
+// so a GitIgnore is safe to share between concurrent traversal workers.
+type GitIgnore struct {
+	root    string
+	absRoot string
+	base    []ignoreFile
+	mu      sync.Mutex
+	dirs    map[string]ignoreFile
+}
+
+// NewGitIgnore Create GitIgnore matcher for the traversal rooted at root
+func NewGitIgnore(root string) (*GitIgnore, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	g := &GitIgnore{root: filepath.Clean(root), absRoot: absRoot, dirs: map[string]ignoreFile{}}
+	top := gitTopLevel(absRoot)
+	if top == "" {
+		top = absRoot
+	}
+	if global := globalExcludesFile(); global != "" {
+		g.base = appendIgnoreFile(g.base, global, top)
+	}
+	g.base = appendIgnoreFile(g.base, filepath.Join(top, ".git", "info", "exclude"), top)
+	// .gitignore files of the ancestors between repository and traversal root
+	rel, err := filepath.Rel(top, absRoot)
+	if err != nil || rel == "." {
+		return g, nil
+	}
+	dir := top
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		g.base = appendIgnoreFile(g.base, filepath.Join(dir, ".gitignore"), dir)
+		dir = filepath.Join(dir, part)
+	}
+	return g, nil
+}
+
+// Ignored Check whether fi, an entry of dir, is ignored. dir must be root of the
+// traversal or one of its descendants as built by joining entry names.
+func (g *GitIgnore) Ignored(dir string, fi os.FileInfo) bool {
+	relDir, err := filepath.Rel(g.root, filepath.Clean(dir))
+	if err != nil {
+		return false
+	}
+	absPath := filepath.Join(g.absRoot, relDir, fi.Name())
+	ignored := false
+	match := func(files []ignoreFile) {
+		for _, f := range files {
+			rel, err := filepath.Rel(f.base, absPath)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+			for _, rule := range f.rules {
+				if rule.dirOnly && !fi.IsDir() {
+					continue
+				}
+				if rule.re.MatchString(rel) {
+					ignored = !rule.negate
+				}
+			}
+		}
+	}
+	match(g.base)
+	match(g.chain(relDir))
+	return ignored
+}
+
+// chain Returns the .gitignore files from the traversal root down to relDir
+func (g *GitIgnore) chain(relDir string) []ignoreFile {
+	files := []ignoreFile{g.load(g.absRoot)}
+	if relDir == "." {
+		return files
+	}
+	dir := g.absRoot
+	for _, part := range strings.Split(relDir, string(filepath.Separator)) {
+		dir = filepath.Join(dir, part)
+		files = append(files, g.load(dir))
+	}
+	return files
+}
+
+func (g *GitIgnore) load(dir string) ignoreFile {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if f, ok := g.dirs[dir]; ok {
+		return f
+	}
+	f := ignoreFile{base: dir}
+	if files := appendIgnoreFile(nil, filepath.Join(dir, ".gitignore"), dir); len(files) > 0 {
+		f = files[0]
+	}
+	g.dirs[dir] = f
+	return f
+}
+
+// appendIgnoreFile Parse the gitignore file at path and append it to files.
+// Missing or unreadable files are silently skipped, just like git does.
+func appendIgnoreFile(files []ignoreFile, path, base string) []ignoreFile {
+	f, err := os.Open(path)
+	if err != nil {
+		return files
+	}
+	defer f.Close()
+	ignore := ignoreFile{base: base}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreLine(scanner.Text()); ok {
+			ignore.rules = append(ignore.rules, rule)
+		}
+	}
+	return append(files, ignore)
+}
+
+// parseIgnoreLine Compile a line of gitignore file, ok is false for blank lines
+// and comments
+func parseIgnoreLine(line string) (ignoreRule, bool) {
+	var rule ignoreRule
+	line = strings.TrimRight(line, "\r")
+	// Trailing spaces are ignored unless escaped with backslash
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false
+	}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\!") || strings.HasPrefix(line, "\\#") {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule, false
+	}
+	// A slash at the beginning or middle anchors the pattern to the gitignore
+	// directory, otherwise it matches at any level below it
+	if !strings.Contains(line, "/") {
+		line = "**/" + line
+	}
+	line = strings.TrimPrefix(line, "/")
+	re, err := regexp.Compile("^" + globToRegexp(line) + "$")
+	if err != nil {
+		return rule, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// globToRegexp Translate gitignore glob into regular expression
+func globToRegexp(glob string) string {
+	var re strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/") && (i == 0 || glob[i-1] == '/'):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**") && i+2 == len(glob) && i > 0 && glob[i-1] == '/':
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			re.WriteString(regexp.QuoteMeta(string(glob[i])))
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				re.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + strings.Replace(class, "\\", "\\\\", -1) + "]")
+			i += end + 1
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return re.String()
+}
+
+// gitTopLevel Find the working tree containing dir, empty if none
+func gitTopLevel(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// globalExcludesFile Location of the user's global excludes file, either
+// core.excludesFile from ~/.gitconfig or $XDG_CONFIG_HOME/git/ignore
+func globalExcludesFile() string {
+	home, err := homedir.Dir()
+	if err != nil {
+		return ""
+	}
+	if f, err := os.Open(filepath.Join(home, ".gitconfig")); err == nil {
+		defer f.Close()
+		section := ""
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if strings.HasPrefix(line, "[") {
+				section = strings.ToLower(strings.Trim(line, "[]"))
+				continue
+			}
+			kv := strings.SplitN(line, "=", 2)
+			if section == "core" && len(kv) == 2 && strings.ToLower(strings.TrimSpace(kv[0])) == "excludesfile" {
+				path, err := homedir.Expand(strings.Trim(strings.TrimSpace(kv[1]), `"`))
+				if err == nil {
+					return path
+				}
+			}
+		}
+	}
+	config := os.Getenv("XDG_CONFIG_HOME")
+	if config == "" {
+		config = filepath.Join(home, ".config")
+	}
+	return filepath.Join(config, "git", "ignore")
+}
+
+// FilterGitIgnored return only those entries of dir which are not ignored by g.
+// The .git directory itself is always filtered out.
+func FilterGitIgnored(fis []os.FileInfo, dir string, g *GitIgnore) []os.FileInfo {
+	return Filter(fis, func(fi os.FileInfo) bool {
+		if fi.Name() == ".git" {
+			return false
+		}
+		return !g.Ignored(dir, fi)
+	})
+}
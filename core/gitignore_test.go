@@ -0,0 +1,77 @@
+package core_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestTraverseWithGitIgnore(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupGitIgnoreTestDir(root_file)
+	defer cleaner()
+	opt.IncludeHidden = true
+	opt.UseGitIgnore = true
+	tree, err := core.TraverseDir(root, opt, 0)
+	if err != nil {
+		t.Errorf("Unable to traverse tree rooted at %s, %v", root, err)
+	}
+	buf := new(bytes.Buffer)
+	tree.Print(buf, opt)
+	output := []byte(fmt.Sprintf(`%s
+├──.gitignore
+├──keep.o
+├──logs
+├──sub
+│  ├──.gitignore
+│  ├──deep
+│  │  └──y.go
+│  └──top.txt
+└──x.tmp
+
+3 directories, 6 files
+`, root_file))
+	if !bytes.Equal(output, buf.Bytes()) {
+		t.Errorf("expected %s, got: \n%s", output, buf.Bytes())
+	}
+}
+
+func TestGitIgnoreFromSubdirectory(t *testing.T) {
+	cleaner, opt, root := helper.SetupGitIgnoreTestDir(uuid.New().String())
+	defer cleaner()
+	opt.UseGitIgnore = true
+	// Rules of the enclosing repository still apply when traversal starts below it
+	tree, err := core.TraverseDir(root+"/logs", opt, 0)
+	if err != nil {
+		t.Errorf("Unable to traverse tree rooted at %s, %v", root, err)
+	}
+	if tree.Stats.DirCount != 0 || tree.Stats.FileCount != 0 {
+		t.Errorf("Expected everything under logs to be ignored, got %+v", tree.Stats)
+	}
+}
+
+func TestGitIgnoreDotDotPrefixedName(t *testing.T) {
+	cleaner, opt, root := helper.SetupGitIgnoreTestDir(uuid.New().String())
+	defer cleaner()
+	// A name starting with .. is still inside the repository
+	if err := ioutil.WriteFile(filepath.Join(root, "..foo.o"), nil, 0666); err != nil {
+		t.Fatalf("Unable to create file, %v", err)
+	}
+	opt.IncludeHidden = true
+	opt.UseGitIgnore = true
+	tree, err := core.TraverseDir(root, opt, 0)
+	if err != nil {
+		t.Errorf("Unable to traverse tree rooted at %s, %v", root, err)
+	}
+	for _, child := range tree.Childrens {
+		if child.Root.Name() == "..foo.o" {
+			t.Errorf("Expected ..foo.o to be ignored by *.o")
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package core
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cespare/xxhash"
+)
+
+// HashAlgorithms Hashes by the name accepted as --hash
+var HashAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha1":   sha1.New,
+	"md5":    md5.New,
+	"xxhash": func() hash.Hash { return xxhash.New() },
+}
+
+// ValidateHash Check that name is one of HashAlgorithms, empty disables hashing
+func ValidateHash(name string) error {
+	if _, ok := HashAlgorithms[name]; ok || name == "" {
+		return nil
+	}
+	names := make([]string, 0, len(HashAlgorithms))
+	for known := range HashAlgorithms {
+		names = append(names, known)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("invalid hash %q, possible hashes are %s", name, strings.Join(names, ", "))
+}
+
+// hashJob Regular file to digest along with the result
+type hashJob struct {
+	node   *Tree
+	path   string
+	digest string
+	err    error
+}
+
+// hashTree Helper private method to digest the content of every regular file
+// in the tree rooted at root, concurrently, and then derive the digest of
+// every directory from its children. Files larger than HashMaxSize are left
+// without digest unless it is negative, failures to read a file are reported
+// on its node.
+func hashTree(tree *Tree, root string, opt Options) {
+	jobs := make([]*hashJob, 0)
+	collectHashJobs(tree, root, opt, &jobs)
+	digestFiles(jobs, HashAlgorithms[opt.Hash], hashWorkers(opt))
+
+	failed := make(map[*Tree]error)
+	for _, job := range jobs {
+		job.node.Stats.setDigest(job.digest, opt.Hash)
+		if job.err != nil {
+			failed[job.node] = job.err
+		}
+	}
+	deriveDigest(tree, opt.Hash, failed)
+}
+
+// digestFiles Helper private method to run the jobs on a pool of workers
+func digestFiles(jobs []*hashJob, newHash func() hash.Hash, workers int) {
+	queue := make(chan *hashJob)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				job.digest, job.err = hashFile(job.path, newHash())
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// hashWorkers Number of files read concurrently, as many as directories are
+// read with Jobs, or one per CPU when the traversal is sequential
+func hashWorkers(opt Options) int {
+	if opt.Jobs > 1 {
+		return opt.Jobs
+	}
+	return runtime.NumCPU()
+}
+
+// collectHashJobs Helper private method to list the regular files to digest
+func collectHashJobs(tree *Tree, p string, opt Options, jobs *[]*hashJob) {
+	if GetFileType(tree.Root) == FILE && tree.Err == nil {
+		if opt.HashMaxSize < 0 || tree.Root.Size() <= opt.HashMaxSize {
+			*jobs = append(*jobs, &hashJob{node: tree, path: p})
+		}
+		return
+	}
+	for i := range tree.Childrens {
+		collectHashJobs(&tree.Childrens[i], filepath.Join(p, tree.Childrens[i].Root.Name()), opt, jobs)
+	}
+}
+
+// hashFile Hex digest of the content of the file at path
+func hashFile(path string, h hash.Hash) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// deriveDigest Helper private method to complete the digests bottom up. The
+// digest of a directory is the hash of the type, digest & name of each child
+// sorted by name, so equal digests mean equal content whatever the order of
+// the listing. Links not followed are digested by their target. Returns the
+// number of files which failed to be read, which are added to the error
+// count of the directories above them.
+func deriveDigest(tree *Tree, algorithm string, failed map[*Tree]error) int {
+	if err, ok := failed[tree]; ok {
+		tree.Err = err
+		tree.Stats.ErrorCount++
+		return 1
+	}
+	if tree.Link != nil && GetFileType(tree.Root) == LINK {
+		h := HashAlgorithms[algorithm]()
+		io.WriteString(h, tree.Link.Target)
+		tree.Stats.setDigest(hex.EncodeToString(h.Sum(nil)), algorithm)
+		return 0
+	}
+	if !tree.Root.IsDir() {
+		return 0
+	}
+	errs := 0
+	for i := range tree.Childrens {
+		errs += deriveDigest(&tree.Childrens[i], algorithm, failed)
+	}
+	tree.Stats.ErrorCount += errs
+	if tree.Err != nil || (tree.Link != nil && tree.Link.Recursive) || !contentKnown(*tree) {
+		return errs
+	}
+	childrens := make([]Tree, len(tree.Childrens))
+	copy(childrens, tree.Childrens)
+	sort.Slice(childrens, func(i, j int) bool {
+		return childrens[i].Root.Name() < childrens[j].Root.Name()
+	})
+	h := HashAlgorithms[algorithm]()
+	for _, child := range childrens {
+		digest := child.Stats.Digest
+		if digest == "" {
+			digest = "-"
+		}
+		fmt.Fprintf(h, "%s %s %s\n", GetFileType(child.Root), digest, child.Root.Name())
+	}
+	tree.Stats.setDigest(hex.EncodeToString(h.Sum(nil)), algorithm)
+	return errs
+}
+
+// contentKnown Check if every directory of the tree was read, so that its
+// digest tells its content. Directories left unread because of MaxLevel or
+// FileLimit would otherwise get the digest of an empty directory.
+func contentKnown(tree Tree) bool {
+	if tree.unread {
+		return false
+	}
+	for _, child := range tree.Childrens {
+		if child.Root.IsDir() && child.Stats.Digest == "" && !contentKnown(child) {
+			return false
+		}
+	}
+	return true
+}
+
+// setDigest Record the digest along with the algorithm it was computed with,
+// empty digest is left without algorithm
+func (stats *Stats) setDigest(digest, algorithm string) {
+	stats.Digest = digest
+	stats.DigestAlgorithm = ""
+	if digest != "" {
+		stats.DigestAlgorithm = algorithm
+	}
+}
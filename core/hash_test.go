@@ -0,0 +1,175 @@
+package core_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestXXHash(t *testing.T) {
+	vectors := map[string]string{
+		"":     "ef46db3751d8e999",
+		"a":    "d24ec4f1a98c6e5b",
+		"asdf": "415872f599cea71e",
+		"Nobody inspects the spammish repetition":                         "fbcea83c8a378bf1",
+		"Call me Ishmael. Some years ago--never mind how long precisely-": "02a2e85470d6fd96",
+	}
+	for input, expected := range vectors {
+		h := core.HashAlgorithms["xxhash"]()
+		h.Write([]byte(input))
+		if got := hex.EncodeToString(h.Sum(nil)); got != expected {
+			t.Errorf("Expected %s for %q, got %s", expected, input, got)
+		}
+		// Writing in small pieces gives the same digest
+		h.Reset()
+		for _, c := range []byte(input) {
+			h.Write([]byte{c})
+		}
+		if got := hex.EncodeToString(h.Sum(nil)); got != expected {
+			t.Errorf("Expected %s for %q written byte by byte, got %s", expected, input, got)
+		}
+	}
+}
+
+func TestTraverseDir_hash(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	content := []byte("package main\n")
+	if err := ioutil.WriteFile(filepath.Join(root, "normal.go"), content, 0644); err != nil {
+		t.Fatalf("Unable to write file, %v", err)
+	}
+	opt.Hash, opt.Jobs = "sha256", 4
+	tree, err := core.TraverseDir(root, opt, -1)
+	if err != nil {
+		t.Fatalf("Unable to traverse the Dir, %v", err)
+	}
+	sum := sha256.Sum256(content)
+	file := findChild(tree, "normal.go")
+	if file == nil || file.Stats.Digest != hex.EncodeToString(sum[:]) || file.Stats.DigestAlgorithm != "sha256" {
+		t.Errorf("Expected sha256 of the content, got %+v", file)
+	}
+	if len(tree.Stats.Digest) != 64 || findChild(tree, "a").Stats.Digest == "" {
+		t.Errorf("Expected directories to have digests, got %q", tree.Stats.Digest)
+	}
+	if extra := core.GetExtra(*file, opt); extra != "[ "+file.Stats.Digest+" ]" {
+		t.Errorf("Expected digest in extras, got %s", extra)
+	}
+
+	// Digest of directory only changes with the content below it
+	again, _ := core.TraverseDir(root, opt, -1)
+	if again.Stats.Digest != tree.Stats.Digest {
+		t.Errorf("Expected same digest for same content, got %s & %s", tree.Stats.Digest, again.Stats.Digest)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "a", "normal.py"), []byte("print(1)\n"), 0644); err != nil {
+		t.Fatalf("Unable to write file, %v", err)
+	}
+	changed, _ := core.TraverseDir(root, opt, -1)
+	if changed.Stats.Digest == tree.Stats.Digest || findChild(changed, "a").Stats.Digest == findChild(tree, "a").Stats.Digest {
+		t.Errorf("Expected digests of the parents to change with the content")
+	}
+	if findChild(changed, "normal.go").Stats.Digest != file.Stats.Digest {
+		t.Errorf("Expected digest of unchanged file to stay the same")
+	}
+
+	// Files larger than the limit are not digested
+	opt.HashMaxSize = 4
+	limited, _ := core.TraverseDir(root, opt, -1)
+	if findChild(limited, "normal.go").Stats.Digest != "" {
+		t.Errorf("Expected file larger than the limit not to be digested")
+	}
+	if extra := core.GetExtra(*findChild(limited, "normal.go"), opt); extra != "[ - ]" {
+		t.Errorf("Expected - for file without digest, got %s", extra)
+	}
+
+	opt.Hash = "crc"
+	if _, err := core.TraverseDir(root, opt, -1); err == nil || !strings.Contains(err.Error(), "sha256") {
+		t.Errorf("Expected error listing the hashes, got %v", err)
+	}
+}
+
+func TestTraverseDir_hashUnreadDirs(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	opt.Hash = "sha256"
+	empty := sha256.Sum256(nil)
+
+	// Directories below the level are not read, their content is unknown
+	opt.MaxLevel = 1
+	tree, _ := core.TraverseDir(root, opt, 0)
+	a := findChild(tree, "a")
+	if a.Stats.Digest != "" || tree.Stats.Digest != "" {
+		t.Errorf("Expected directories not read to have no digest, got %q & %q", a.Stats.Digest, tree.Stats.Digest)
+	}
+	if findChild(tree, "normal.go").Stats.Digest == "" {
+		t.Errorf("Expected files to be digested")
+	}
+	if extra := core.GetExtra(*a, opt); extra != "[ - ]" {
+		t.Errorf("Expected - for directory not read, got %s", extra)
+	}
+
+	// So are directories with more entries than the limit
+	opt.MaxLevel, opt.FileLimit = -1, 2
+	tree, _ = core.TraverseDir(root, opt, 0)
+	if a := findChild(tree, "a"); a.Stats.Digest != "" || tree.Stats.Digest != "" {
+		t.Errorf("Expected directories over the limit to have no digest, got %q & %q", a.Stats.Digest, tree.Stats.Digest)
+	}
+
+	// Empty directories which were read keep the digest of their content
+	opt.FileLimit = -1
+	tree, _ = core.TraverseDir(filepath.Join(root, "a", "c", "d", "e"), opt, 0)
+	if tree.Stats.Digest != hex.EncodeToString(empty[:]) {
+		t.Errorf("Expected digest of empty directory, got %q", tree.Stats.Digest)
+	}
+}
+
+func TestDiff_digest(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	opt.Hash = "xxhash"
+	file := filepath.Join(root, "normal.go")
+	if err := ioutil.WriteFile(file, []byte("before"), 0644); err != nil {
+		t.Fatalf("Unable to write file, %v", err)
+	}
+	info, _ := os.Stat(file)
+	a, _ := core.TraverseDir(root, opt, -1)
+	// Same size & modification time, only the content tells the change
+	if err := ioutil.WriteFile(file, []byte("after!"), 0644); err != nil {
+		t.Fatalf("Unable to write file, %v", err)
+	}
+	os.Chtimes(file, info.ModTime(), info.ModTime())
+	b, _ := core.TraverseDir(root, opt, -1)
+	diff, stats := core.Diff(a, b, core.DefaultDiffOptions())
+	if stats.Modified != 1 {
+		t.Errorf("Expected 1 modified entry, got %+v", stats)
+	}
+	for _, child := range diff.Childrens {
+		if child.Name == "normal.go" && (child.Change != core.MODIFIED || child.Reasons[0] != "digest") {
+			t.Errorf("Expected normal.go modified by digest, got %+v", child)
+		}
+	}
+
+	// Digests of different algorithms are not compared
+	opt.Hash = "md5"
+	b, _ = core.TraverseDir(root, opt, -1)
+	if _, stats := core.Diff(a, b, core.DefaultDiffOptions()); stats.Modified != 0 {
+		t.Errorf("Expected no change between digests of different hashes, got %+v", stats)
+	}
+}
+
+// findChild Direct child of tree by name
+func findChild(tree core.Tree, name string) *core.Tree {
+	for i := range tree.Childrens {
+		if tree.Childrens[i].Root.Name() == name {
+			return &tree.Childrens[i]
+		}
+	}
+	return nil
+}
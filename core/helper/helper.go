@@ -1,8 +1,10 @@
 package helper
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	tree "github.com/marshal003/hitree/core"
 )
@@ -59,3 +61,90 @@ func SetupFlattenTestDir(root string) (Cleaner, tree.Options, string) {
 		os.RemoveAll(tempDir)
 	}, opt, tempDir
 }
+
+// SetupGitIgnoreTestDir Helper utility to setup a git working tree in temp path
+// with ignore rules at multiple levels, and then return a cleaner function.
+//
+// root
+// 	.git/info/exclude	(*.log)
+// 	.gitignore		(build/, *.o, !keep.o, /top.txt, logs/**)
+// 	a.o, app.log, keep.o, top.txt, x.tmp
+// 	build/x.go
+// 	logs/a/b.txt
+// 	sub
+// 		.gitignore	(*.tmp)
+// 		top.txt, x.tmp
+// 		deep/y.go
+func SetupGitIgnoreTestDir(root string) (Cleaner, tree.Options, string) {
+	tree.InitAurora(false)
+	opt := tree.DefaultOptions()
+	pwd, _ := os.Getwd()
+	tempDir := filepath.Join(os.TempDir(), root)
+	os.RemoveAll(tempDir)
+	files := map[string]string{
+		".git/info/exclude": "*.log\n",
+		".gitignore":        "# build output\nbuild/\n*.o\n!keep.o\n/top.txt\nlogs/**\n",
+		"a.o":               "",
+		"app.log":           "",
+		"keep.o":            "",
+		"top.txt":           "",
+		"x.tmp":             "",
+		"build/x.go":        "",
+		"logs/a/b.txt":      "",
+		"sub/.gitignore":    "*.tmp\n",
+		"sub/top.txt":       "",
+		"sub/x.tmp":         "",
+		"sub/deep/y.go":     "",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0777)
+		ioutil.WriteFile(path, []byte(content), 0666)
+	}
+	os.Chdir(tempDir)
+	return func() {
+		os.Chdir(pwd)
+		os.RemoveAll(tempDir)
+	}, opt, tempDir
+}
+
+// SetupSortTestDir Helper utility to setup a flat test directory in temp path
+// with entries of distinct sizes & modification times, and then return a
+// cleaner function.
+//
+// root
+// 	file9.md	(300 bytes, oldest)
+// 	file10.txt	(200 bytes)
+// 	small.go	(10 bytes)
+// 	dir		(newest)
+func SetupSortTestDir(root string) (Cleaner, tree.Options, string) {
+	tree.InitAurora(false)
+	opt := tree.DefaultOptions()
+	pwd, _ := os.Getwd()
+	tempDir := filepath.Join(os.TempDir(), root)
+	os.RemoveAll(tempDir)
+	os.MkdirAll(filepath.Join(tempDir, "dir"), 0777)
+	entries := []struct {
+		name string
+		size int
+	}{
+		{"file9.md", 300},
+		{"file10.txt", 200},
+		{"small.go", 10},
+		{"dir", 0},
+	}
+	now := time.Now()
+	for i, e := range entries {
+		path := filepath.Join(tempDir, e.name)
+		if e.size > 0 {
+			ioutil.WriteFile(path, make([]byte, e.size), 0666)
+		}
+		mtime := now.Add(time.Duration(i-len(entries)) * time.Hour)
+		os.Chtimes(path, mtime, mtime)
+	}
+	os.Chdir(tempDir)
+	return func() {
+		os.Chdir(pwd)
+		os.RemoveAll(tempDir)
+	}, opt, tempDir
+}
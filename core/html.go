@@ -0,0 +1,123 @@
+package core
+
+import (
+	"html/template"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// htmlNode View model of a tree node rendered by htmlTemplate
+type htmlNode struct {
+	Name      string
+	Href      string
+	IsDir     bool
+	Columns   []string
+	Childrens []htmlNode
+}
+
+// htmlPage View model of the whole page rendered by htmlTemplate
+type htmlPage struct {
+	Title   string
+	Headers []string
+	Root    htmlNode
+	Report  string
+}
+
+// htmlTemplate Self contained page, directories are collapsible using
+// details/summary elements so no script is required
+var htmlTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: monospace; }
+ul { list-style: none; margin: 0; padding-left: 1.5em; border-left: 1px dotted #999; }
+summary { cursor: pointer; }
+.row { white-space: nowrap; }
+.col { display: inline-block; min-width: 8em; color: #666; }
+.header { font-weight: bold; }
+a { text-decoration: none; }
+a.dir { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Headers}}<div class="row header">{{range .Headers}}<span class="col">{{.}}</span>{{end}}name</div>
+{{end}}{{template "node" .Root}}
+{{if .Report}}<p class="report">{{.Report}}</p>
+{{end}}</body>
+</html>
+{{define "node"}}{{if .IsDir}}<details open><summary>{{template "row" .}}</summary>
+<ul>
+{{range .Childrens}}<li>{{template "node" .}}</li>
+{{end}}</ul>
+</details>{{else}}{{template "row" .}}{{end}}{{end}}
+{{define "row"}}<span class="row">{{range .Columns}}<span class="col">{{.}}</span>{{end}}<a{{if .IsDir}} class="dir"{{end}} href="{{.Href}}">{{.Name}}</a></span>{{end}}
+`))
+
+// AsHTML Write the tree as a self contained HTML page with collapsible
+// directories. Entries are hyperlinked relative to opt.HTMLBaseURL, extra
+// details enabled in options are rendered as columns and opt.HTMLTitle is used
+// as title of the page, defaulting to the name of the root.
+func (tree Tree) AsHTML(w io.Writer, opt Options) error {
+	name, err := tree.NodeName(opt)
+	if err != nil {
+		return err
+	}
+	page := htmlPage{
+		Title:   opt.HTMLTitle,
+		Headers: extraHeaders(opt),
+		Root:    tree.asHTMLNode(name, "", opt),
+	}
+	if page.Title == "" {
+		page.Title = name
+	}
+	if !opt.NoReport {
+		page.Report = report(tree.Stats, opt)
+	}
+	return htmlTemplate.Execute(w, page)
+}
+
+// asHTMLNode Helper private method to build the view model, rel is the
+// slash separated path of the node relative to the root
+func (tree Tree) asHTMLNode(name, rel string, opt Options) htmlNode {
+	node := htmlNode{
+		Name:    name,
+		Href:    htmlLink(opt.HTMLBaseURL, rel, tree.Root.IsDir()),
+		IsDir:   tree.Root.IsDir(),
+		Columns: extraColumns(tree, opt),
+	}
+	for _, subtree := range tree.Childrens {
+		if canPrune(subtree, opt) {
+			continue
+		}
+		childName := subtree.Root.Name()
+		node.Childrens = append(node.Childrens, subtree.asHTMLNode(childName, path.Join(rel, childName), opt))
+	}
+	return node
+}
+
+// htmlLink Link of the entry at rel, relative to base. Path segments are
+// escaped and directories end with a slash.
+func htmlLink(base, rel string, isDir bool) string {
+	segments := make([]string, 0)
+	for _, s := range strings.Split(rel, "/") {
+		if s != "" {
+			segments = append(segments, url.PathEscape(s))
+		}
+	}
+	link := strings.Join(segments, "/")
+	if base != "" {
+		link = strings.TrimSuffix(base, "/") + "/" + link
+	}
+	if link == "" {
+		link = "."
+	}
+	if isDir && !strings.HasSuffix(link, "/") {
+		link = link + "/"
+	}
+	return link
+}
@@ -0,0 +1,44 @@
+package core_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestTreeAsHTML(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	opt.Prune = true
+	opt.PrintSize = true
+	opt.HTMLBaseURL = "https://example.com/builds/"
+	opt.HTMLTitle = "Build <42>"
+	tree, err := core.TraverseDir(root, opt, 0)
+	if err != nil {
+		t.Errorf("Unable to traverse the Dir")
+	}
+	buf := new(bytes.Buffer)
+	if err := tree.AsHTML(buf, opt); err != nil {
+		t.Errorf("Unable to render HTML, %v", err)
+	}
+	page := buf.String()
+	for _, expected := range []string{
+		"<title>Build &lt;42&gt;</title>",
+		`<span class="col">size</span>name`,
+		`<a class="dir" href="https://example.com/builds/a/c/d/">d</a>`,
+		`<span class="col">0</span><a href="https://example.com/builds/a/c/normal.go">normal.go</a>`,
+		`<a href="https://example.com/builds/a/c/d/normal.py">normal.py</a>`,
+		`<p class="report">5 directories, 5 files</p>`,
+	} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("Expected page to contain %s, got %s", expected, page)
+		}
+	}
+	if strings.Contains(page, "/a/c/d/e/") {
+		t.Errorf("Expected empty directory e to be pruned, got %s", page)
+	}
+}
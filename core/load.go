@@ -0,0 +1,79 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// LoadJSON Read a tree exported by AsJSONString back into a Tree. Nodes are
+// backed by VirtualFileInfo, so the snapshot can be rendered on machines where
+// the paths don't exist. Size, modification time & mode are taken from the
+// stats when the snapshot was exported with them, otherwise Stats.Present is
+// false. Counts are recomputed.
+// Use Reshape to apply filtering, sorting, level & dironly options.
+func LoadJSON(r io.Reader) (Tree, error) {
+	var jsonTree JSONTree
+	if err := json.NewDecoder(r).Decode(&jsonTree); err != nil {
+		return Tree{}, err
+	}
+	return jsonTree.toTree()
+}
+
+// toTree Convert JSONTree into Tree
+func (jsonTree JSONTree) toTree() (Tree, error) {
+	if jsonTree.Name == "" {
+		return Tree{}, fmt.Errorf("invalid JSON tree, node without name")
+	}
+	fi := VirtualFileInfo{FName: jsonTree.Name, FMode: FileModeOf(jsonTree.FType)}
+	// Without stats in the snapshot only the counts are known, which are
+	// recomputed below
+	var stats Stats
+	if jsonTree.FStats != nil {
+		fi.FSize = jsonTree.FStats.Size
+		fi.FModTime = jsonTree.FStats.ModificationTime
+		// Snapshots exported before the mode was recorded only have the
+		// permission
+		if perm, ok := parseModeString(modeOf(*jsonTree.FStats, Options{})); ok {
+			fi.FMode = fi.FMode&os.ModeType | perm
+		}
+		stats = *jsonTree.FStats
+		stats.DirCount, stats.FileCount, stats.ErrorCount = 0, 0, 0
+		stats.present = true
+	}
+	var nodeErr error
+	if jsonTree.Error != "" {
+		nodeErr = errors.New(jsonTree.Error)
+		stats.ErrorCount++
+	}
+	childrens := make([]Tree, 0, len(jsonTree.SubTree))
+	for _, sub := range jsonTree.SubTree {
+		subtree, err := sub.toTree()
+		if err != nil {
+			return Tree{}, err
+		}
+		stats = updateStats(subtree, stats, Options{})
+		childrens = append(childrens, subtree)
+	}
+	tree := Tree{Root: fi, Childrens: childrens, Stats: stats, Err: nodeErr, DuplicateOf: jsonTree.DuplicateOf}
+	if jsonTree.Target != "" {
+		tree.Link = &Link{Target: jsonTree.Target, Dangling: jsonTree.Dangling, Recursive: jsonTree.Recursive}
+	}
+	return tree, nil
+}
+
+// LoadJSONFile Read a tree exported by AsJSONString from the file at path,
+// or from stdin if path is "-"
+func LoadJSONFile(path string) (Tree, error) {
+	if path == "-" {
+		return LoadJSON(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return Tree{}, err
+	}
+	defer f.Close()
+	return LoadJSON(f)
+}
@@ -0,0 +1,118 @@
+package core_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestLoadJSONRoundTrip(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	opt.JSONIncludeStats = true
+	opt.PrintSize = true
+	opt.PrintProtection = true
+	tree, err := core.TraverseDir(root, opt, 0)
+	if err != nil {
+		t.Errorf("Unable to traverse the Dir")
+	}
+	snapshot, err := tree.AsJSONString(opt)
+	if err != nil {
+		t.Errorf("Unable to export the tree, %v", err)
+	}
+
+	loaded, err := core.LoadJSON(bytes.NewReader(snapshot))
+	if err != nil {
+		t.Errorf("Unable to load the tree, %v", err)
+	}
+	expected, buf := new(bytes.Buffer), new(bytes.Buffer)
+	tree.Print(expected, opt)
+	loaded.Print(buf, opt)
+	if !bytes.Equal(expected.Bytes(), buf.Bytes()) {
+		t.Errorf("expected %s, got: \n%s", expected.Bytes(), buf.Bytes())
+	}
+	// Access time is exported along with the other stats
+	if loaded.Stats.AccessTime.IsZero() || !loaded.Stats.AccessTime.Equal(tree.Stats.AccessTime) {
+		t.Errorf("Expected access time %s, got %s", tree.Stats.AccessTime, loaded.Stats.AccessTime)
+	}
+
+	// Display options are applied on the loaded tree like on traversal
+	opt.DirOnly = true
+	opt.MaxLevel = 2
+	tree, _ = core.TraverseDir(root, opt, 0)
+	loaded, err = loaded.Reshape(opt)
+	if err != nil {
+		t.Errorf("Unable to reshape the tree, %v", err)
+	}
+	expected.Reset()
+	buf.Reset()
+	tree.Print(expected, opt)
+	loaded.Print(buf, opt)
+	if !bytes.Equal(expected.Bytes(), buf.Bytes()) {
+		t.Errorf("expected %s, got: \n%s", expected.Bytes(), buf.Bytes())
+	}
+}
+
+func TestLoadJSONInvalid(t *testing.T) {
+	for _, input := range []string{`{"name": "a", "subtree": [`, `{"file_type": "dir"}`} {
+		if _, err := core.LoadJSON(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected error for %s", input)
+		}
+	}
+}
+
+func TestLoadJSONWithoutStats(t *testing.T) {
+	loaded, err := core.LoadJSON(strings.NewReader(`{"name": "root", "file_type": "dir", "subtree": [{"name": "a.txt", "file_type": "file", "subtree": []}]}`))
+	if err != nil {
+		t.Fatalf("Unable to load the tree, %v", err)
+	}
+	file := loaded.Childrens[0]
+	if loaded.Stats.Present() || file.Stats.Present() {
+		t.Errorf("Expected stats of snapshot without stats to be unknown")
+	}
+	if file.Stats.Permission != "" || !file.Stats.ModificationTime.IsZero() {
+		t.Errorf("Expected no invented stats, got %+v", file.Stats)
+	}
+	if loaded.Stats.FileCount != 1 {
+		t.Errorf("Expected counts to be recomputed, got %+v", loaded.Stats)
+	}
+}
+
+func TestLoadJSONExecutable(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupFlattenTestDir(root_file)
+	defer cleaner()
+	ioutil.WriteFile(filepath.Join(root, "run.sh"), nil, 0755)
+	os.Chmod(filepath.Join(root, "run.sh"), 0755|os.ModeSetuid)
+	opt.JSONIncludeStats = true
+	opt.Classify = true
+	opt.PrintProtection = true
+	tree, _ := core.TraverseDir(root, opt, 0)
+	snapshot, err := tree.AsJSONString(opt)
+	if err != nil {
+		t.Fatalf("Unable to export the tree, %v", err)
+	}
+	loaded, err := core.LoadJSON(bytes.NewReader(snapshot))
+	if err != nil {
+		t.Fatalf("Unable to load the tree, %v", err)
+	}
+	// The mode recorded in the stats is kept, so executables are still marked
+	expected, buf := new(bytes.Buffer), new(bytes.Buffer)
+	tree.Print(expected, opt)
+	loaded.Print(buf, opt)
+	if !bytes.Equal(expected.Bytes(), buf.Bytes()) || !strings.Contains(buf.String(), "run.sh*") {
+		t.Errorf("expected %s, got: \n%s", expected.Bytes(), buf.Bytes())
+	}
+	for _, child := range loaded.Childrens {
+		if child.Root.Name() == "run.sh" && child.Root.Mode() != 0755|os.ModeSetuid {
+			t.Errorf("Expected mode of run.sh to be kept, got %s", child.Root.Mode())
+		}
+	}
+}
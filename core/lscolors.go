@@ -0,0 +1,125 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LSColors Colors parsed from the LS_COLORS environment variable used by ls,
+// by type code (di, ln, ex, ...) and by suffix of the name (*.tar)
+type LSColors struct {
+	types    map[string]string
+	suffixes []lsSuffix
+}
+
+// lsSuffix Color of the names ending with suffix
+type lsSuffix struct {
+	suffix string
+	sgr    string
+}
+
+// lsTypeCodes LS_COLORS code of each fileType
+var lsTypeCodes = map[FileType]string{
+	FILE:     "fi",
+	DIR:      "di",
+	LINK:     "ln",
+	FIFO:     "pi",
+	SOCKET:   "so",
+	CHARDEV:  "cd",
+	BLOCKDEV: "bd",
+}
+
+// ParseLSColors Parse colors in the format of LS_COLORS, colon separated
+// code=SGR entries like di=01;34:ln=target:*.tar=38;5;9. Codes which hitree
+// doesn't use are accepted and ignored.
+func ParseLSColors(s string) (*LSColors, error) {
+	colors := &LSColors{types: map[string]string{}}
+	for _, entry := range strings.Split(s, ":") {
+		if entry == "" {
+			continue
+		}
+		eq := strings.Index(entry, "=")
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid LS_COLORS entry %q", entry)
+		}
+		key, sgr := entry[:eq], entry[eq+1:]
+		if !(key == "ln" && sgr == "target") && strings.Trim(sgr, "0123456789;") != "" {
+			return nil, fmt.Errorf("invalid LS_COLORS sequence %q for %s", sgr, key)
+		}
+		if strings.HasPrefix(key, "*") {
+			colors.suffixes = append(colors.suffixes, lsSuffix{suffix: key[1:], sgr: sgr})
+		} else {
+			colors.types[key] = sgr
+		}
+	}
+	return colors, nil
+}
+
+// Disable Drop the rules of the given type codes, so the nodes they match keep
+// the colors of options, e.g. the ones set explicitly by flags. Disabling fi
+// drops the rules by suffix as well.
+func (colors *LSColors) Disable(codes ...string) {
+	for _, code := range codes {
+		delete(colors.types, code)
+		if code == "fi" {
+			colors.suffixes = nil
+		}
+	}
+}
+
+// LSColorsFromEnv Colors from LS_COLORS environment variable, nil if it is not
+// set or empty
+func LSColorsFromEnv() (*LSColors, error) {
+	s := os.Getenv("LS_COLORS")
+	if s == "" {
+		return nil, nil
+	}
+	return ParseLSColors(s)
+}
+
+// colorOf Color of the node following the rules of ls, ok is false when
+// LS_COLORS has no rule for it and the colors of options should be used
+func (colors *LSColors) colorOf(tree Tree) (Colorize, bool) {
+	ftype := GetFileType(tree.Root)
+	if tree.Link != nil {
+		if sgr, ok := colors.types["or"]; ok && tree.Link.Dangling {
+			return sgrColor(sgr), true
+		}
+		sgr, ok := colors.types["ln"]
+		if !ok {
+			return nil, false
+		}
+		if sgr != "target" {
+			return sgrColor(sgr), true
+		}
+		// ln=target colors the link like its target, which is only known when
+		// the link was followed, otherwise like a file
+		if ftype == LINK {
+			ftype = FILE
+		}
+	}
+	if ftype == FILE && IsExecutable(tree.Root) {
+		sgr, ok := colors.types["ex"]
+		if !ok {
+			return nil, false
+		}
+		return sgrColor(sgr), true
+	}
+	if ftype == FILE {
+		name := tree.Root.Name()
+		// Rules defined later take precedence, like in ls
+		for i := len(colors.suffixes) - 1; i >= 0; i-- {
+			if strings.HasSuffix(name, colors.suffixes[i].suffix) {
+				return sgrColor(colors.suffixes[i].sgr), true
+			}
+		}
+	}
+	if sgr, ok := colors.types[lsTypeCodes[ftype]]; ok {
+		return sgrColor(sgr), true
+	}
+	if sgr, ok := colors.types["no"]; ok && ftype == FILE {
+		return sgrColor(sgr), true
+	}
+	return nil, false
+}
@@ -0,0 +1,65 @@
+package core_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestLSColors(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupFlattenTestDir(root_file)
+	defer cleaner()
+	defer core.InitAurora(false)
+	os.Symlink("normal.go", filepath.Join(root, "link"))
+	os.Symlink("missing", filepath.Join(root, "orphan"))
+	colors, err := core.ParseLSColors("rs=0:di=01;34:ln=01;36:or=31:*.go=38;5;208:*.py=32:*.pyc=90")
+	if err != nil {
+		t.Fatalf("Unable to parse LS_COLORS, %v", err)
+	}
+	core.InitAurora(true)
+	opt.LSColors = colors
+	opt.NoReport = true
+	opt.IncludePattern = []string{"*.go", "*.py*", "normal.txt", "link", "orphan"}
+	tree, _ := core.TraverseDir(root, opt, 0)
+	buf := new(bytes.Buffer)
+	tree.Print(buf, opt)
+	// Branches & files without rule keep the colors of options
+	tee := core.ColorMap["gray"]("├──").String()
+	file := func(s string) string { return core.ColorMap["gray"](s).String() }
+	color := func(sgr, s string) string { return fmt.Sprintf("\033[%sm%s\033[0m", sgr, s) }
+	expected := color("01;34", root_file) + "\n" +
+		tee + color("01;34", "a") + "\n" +
+		tee + color("38;5;208", "golang.go") + "\n" +
+		tee + color("01;36", "link") + " -> normal.go\n" +
+		tee + color("38;5;208", "normal.go") + "\n" +
+		tee + color("32", "normal.py") + "\n" +
+		tee + file("") + file("normal.txt") + "\n" +
+		tee + color("31", "orphan") + " -> missing " + core.ColorMap["gray"]("[dangling]").String() + "\n" +
+		tee + color("32", "python.py") + "\n" +
+		core.ColorMap["gray"]("└──").String() + color("90", "python.pyc") + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got: \n%q", expected, buf.String())
+	}
+
+	// Nodes of disabled codes keep the colors of options
+	colors.Disable("fi", "ln")
+	buf.Reset()
+	tree.Print(buf, opt)
+	if line := tee + file("") + file("golang.go") + "\n"; !bytes.Contains(buf.Bytes(), []byte(line)) {
+		t.Errorf("expected %q in: \n%q", line, buf.String())
+	}
+	if line := tee + file("") + file("link") + " -> normal.go\n"; !bytes.Contains(buf.Bytes(), []byte(line)) {
+		t.Errorf("expected %q in: \n%q", line, buf.String())
+	}
+
+	if _, err := core.ParseLSColors("di=bold"); err == nil {
+		t.Errorf("Expected error for invalid sequence")
+	}
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// markdownEscaper Escapes the characters of names which markdown would
+// otherwise interpret as formatting
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+// AsMarkdown Write the tree for inclusion in markdown documents. By default it
+// is the text output without colors in a fenced code block. With
+// opt.MarkdownList it is a nested bullet list instead, where entries are linked
+// relative to opt.HTMLBaseURL when opt.MarkdownLinks is set.
+func (tree Tree) AsMarkdown(w io.Writer, opt Options) error {
+	opt = opt.withoutColors()
+	if !opt.MarkdownList {
+		fmt.Fprintf(w, "```\n")
+		tree.Print(w, opt)
+		fmt.Fprintf(w, "```\n")
+		return nil
+	}
+	name, err := tree.NodeName(opt)
+	if err != nil {
+		return err
+	}
+	tree.printMarkdownItem(w, name, "", 0, opt)
+	if !opt.NoReport {
+		fmt.Fprintf(w, "\n%s\n", report(tree.Stats, opt))
+	}
+	return nil
+}
+
+// printMarkdownItem Helper private method to recursively print the bullet list,
+// rel is the slash separated path of the node relative to the root
+func (tree Tree) printMarkdownItem(w io.Writer, name, rel string, depth int, opt Options) {
+	item := markdownEscaper.Replace(name)
+	if opt.MarkdownLinks {
+		item = fmt.Sprintf("[%s](%s)", item, markdownLink(opt.HTMLBaseURL, rel, tree.Root.IsDir()))
+	}
+	if extra := GetExtra(tree, opt); extra != "" {
+		item = fmt.Sprintf("`%s` %s", extra, item)
+	}
+	fmt.Fprintf(w, "%s- %s\n", strings.Repeat("  ", depth), item)
+	for _, subtree := range tree.Childrens {
+		if canPrune(subtree, opt) {
+			continue
+		}
+		childName := subtree.Root.Name()
+		subtree.printMarkdownItem(w, childName, path.Join(rel, childName), depth+1, opt)
+	}
+}
+
+// markdownLink Link of the entry like htmlLink, with parentheses and spaces
+// escaped as well since they end the link destination in markdown
+func markdownLink(base, rel string, isDir bool) string {
+	return strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(htmlLink(base, rel, isDir))
+}
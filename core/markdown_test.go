@@ -0,0 +1,58 @@
+package core_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestAsMarkdown(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	opt.MaxLevel = 1
+	tree, err := core.TraverseDir(root, opt, -1)
+	if err != nil {
+		t.Errorf("Unable to traverse the Dir")
+	}
+	buf := new(bytes.Buffer)
+	if err := tree.AsMarkdown(buf, opt); err != nil {
+		t.Errorf("Unable to write markdown, %v", err)
+	}
+	output := fmt.Sprintf("```\n%s\n├──a\n│  ├──b\n│  ├──c\n│  └──normal.py\n└──normal.go\n\n3 directories, 2 files\n```\n", root_file)
+	if output != buf.String() {
+		t.Errorf("expected %s, got: \n%s", output, buf.String())
+	}
+}
+
+func TestAsMarkdown_links(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	opt.MaxLevel = 1
+	opt.NoReport = true
+	opt.MarkdownList = true
+	opt.MarkdownLinks = true
+	tree, err := core.TraverseDir(root, opt, -1)
+	if err != nil {
+		t.Errorf("Unable to traverse the Dir")
+	}
+	buf := new(bytes.Buffer)
+	if err := tree.AsMarkdown(buf, opt); err != nil {
+		t.Errorf("Unable to write markdown, %v", err)
+	}
+	output := fmt.Sprintf(`- [%s](./)
+  - [a](a/)
+    - [b](a/b/)
+    - [c](a/c/)
+    - [normal.py](a/normal.py)
+  - [normal.go](normal.go)
+`, root_file)
+	if output != buf.String() {
+		t.Errorf("expected %s, got: \n%s", output, buf.String())
+	}
+}
@@ -0,0 +1,75 @@
+package core
+
+import "os"
+
+// modeTypes Character of the type of file in the mode string, like ls -l
+var modeTypes = []struct {
+	mode os.FileMode
+	char byte
+}{
+	{os.ModeDir, 'd'},
+	{os.ModeSymlink, 'l'},
+	{os.ModeNamedPipe, 'p'},
+	{os.ModeSocket, 's'},
+	{os.ModeCharDevice, 'c'},
+	{os.ModeDevice, 'b'},
+}
+
+// ModeString Mode in the format of ls -l, like drwxr-sr-x or -rwsr-xr-t,
+// where setuid, setgid & sticky bits replace the execute bits they go with
+func ModeString(mode os.FileMode) string {
+	buf := []byte("----------")
+	for _, t := range modeTypes {
+		if mode&t.mode != 0 {
+			buf[0] = t.char
+			break
+		}
+	}
+	const rwx = "rwxrwxrwx"
+	for i := 0; i < 9; i++ {
+		if mode&(1<<uint(8-i)) != 0 {
+			buf[i+1] = rwx[i]
+		}
+	}
+	special := func(bit os.FileMode, i int, set, unset byte) {
+		if mode&bit == 0 {
+			return
+		}
+		if buf[i] == 'x' {
+			buf[i] = set
+		} else {
+			buf[i] = unset
+		}
+	}
+	special(os.ModeSetuid, 3, 's', 'S')
+	special(os.ModeSetgid, 6, 's', 'S')
+	special(os.ModeSticky, 9, 't', 'T')
+	return string(buf)
+}
+
+// parseModeString Permission & setuid, setgid, sticky bits of a mode string
+// written by ModeString or os.FileMode.String, ok is false if it is malformed.
+// The type of file is left out.
+func parseModeString(s string) (mode os.FileMode, ok bool) {
+	if len(s) != 10 {
+		return 0, false
+	}
+	special := map[int]os.FileMode{3: os.ModeSetuid, 6: os.ModeSetgid, 9: os.ModeSticky}
+	const rwx = "rwxrwxrwx"
+	for i := 0; i < 9; i++ {
+		c := s[i+1]
+		bit := os.FileMode(1 << uint(8-i))
+		switch {
+		case c == rwx[i]:
+			mode |= bit
+		case c == '-':
+		case special[i+1] != 0 && (c == 's' || c == 't'):
+			mode |= bit | special[i+1]
+		case special[i+1] != 0 && (c == 'S' || c == 'T'):
+			mode |= special[i+1]
+		default:
+			return 0, false
+		}
+	}
+	return mode, true
+}
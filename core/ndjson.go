@@ -0,0 +1,79 @@
+package core
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// NDJSONEntry A single line of NDJSON (JSON Lines) output describing one node.
+// Parent is empty for the root.
+type NDJSONEntry struct {
+	Path   string     `json:"path"`
+	Name   string     `json:"name"`
+	Depth  int        `json:"depth"`
+	Parent string     `json:"parent"`
+	FType  FileType   `json:"type"`
+	Stats  EntryStats `json:"stats"`
+	Error  string     `json:"error,omitempty"`
+	Target string     `json:"target,omitempty"`
+}
+
+// EntryStats Stats of a single node, without the counts of its content which
+// are not known while streaming
+type EntryStats struct {
+	Size             int64     `json:"size"`
+	DiskUsage        int64     `json:"disk_usage"`
+	ModificationTime time.Time `json:"mod_time"`
+	Permission       string    `json:"permission"`
+	Owner            string    `json:"owner,omitempty"`
+	Group            string    `json:"group,omitempty"`
+	Digest           string    `json:"digest,omitempty"`
+	DigestAlgorithm  string    `json:"digest_algorithm,omitempty"`
+}
+
+func newNDJSONEntry(record nodeRecord) NDJSONEntry {
+	entry := NDJSONEntry{
+		Path:   record.path,
+		Name:   record.fi.Name(),
+		Depth:  record.depth,
+		Parent: record.parent,
+		FType:  GetFileType(record.fi),
+		Stats: EntryStats{
+			Size:             record.stats.Size,
+			DiskUsage:        record.stats.DiskUsage,
+			ModificationTime: record.stats.ModificationTime,
+			Permission:       record.stats.Permission,
+			Owner:            record.stats.Owner,
+			Group:            record.stats.Group,
+			Digest:           record.stats.Digest,
+			DigestAlgorithm:  record.stats.DigestAlgorithm,
+		},
+	}
+	if record.err != nil {
+		entry.Error = errorReason(record.err)
+	}
+	if record.link != nil {
+		entry.Target = record.link.Target
+	}
+	return entry
+}
+
+// StreamNDJSON walks the directory rooted at root and writes one JSON object
+// per node to w as soon as it is discovered, so that the tree never needs to be
+// held in memory. Options are handled like by StreamTree.
+func StreamNDJSON(w io.Writer, root string, opt Options) (Stats, error) {
+	enc := json.NewEncoder(w)
+	return streamRecords(root, opt, func(record nodeRecord) error {
+		return enc.Encode(newNDJSONEntry(record))
+	})
+}
+
+// WriteNDJSON Write one JSON object per node of the tree to w, in the order
+// they are printed. Paths are built from the name of the root.
+func (tree Tree) WriteNDJSON(w io.Writer, opt Options) error {
+	enc := json.NewEncoder(w)
+	return tree.walkRecords(opt, func(record nodeRecord) error {
+		return enc.Encode(newNDJSONEntry(record))
+	})
+}
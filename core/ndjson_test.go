@@ -0,0 +1,64 @@
+package core_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func decodeNDJSON(t *testing.T, data []byte) []string {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var entry core.NDJSONEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Errorf("Invalid JSON line %s, %v", scanner.Text(), err)
+		}
+		lines = append(lines, fmt.Sprintf("%d %s %s %s %s", entry.Depth, entry.FType, entry.Parent, entry.Path, entry.Name))
+	}
+	return lines
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	opt.MaxLevel = 2
+	buf := new(bytes.Buffer)
+	stats, err := core.StreamNDJSON(buf, root, opt)
+	if err != nil {
+		t.Errorf("Unable to stream the Dir, %v", err)
+	}
+	if stats.DirCount != 3 || stats.FileCount != 2 {
+		t.Errorf("Expected 3 directories & 2 files, got %+v", stats)
+	}
+	expected := []string{
+		fmt.Sprintf("0 dir  %s %s", root_file, root_file),
+		fmt.Sprintf("1 dir %s %s/a a", root_file, root_file),
+		fmt.Sprintf("2 dir %s/a %s/a/b b", root_file, root_file),
+		fmt.Sprintf("2 dir %s/a %s/a/c c", root_file, root_file),
+		fmt.Sprintf("2 file %s/a %s/a/normal.py normal.py", root_file, root_file),
+		fmt.Sprintf("1 file %s %s/normal.go normal.go", root_file, root_file),
+	}
+	lines := decodeNDJSON(t, buf.Bytes())
+	if fmt.Sprint(lines) != fmt.Sprint(expected) {
+		t.Errorf("Expected %q, got %q", expected, lines)
+	}
+
+	// Writing an in memory tree produces the same entries
+	tree, _ := core.TraverseDir(root, opt, 0)
+	buf.Reset()
+	if err := tree.WriteNDJSON(buf, opt); err != nil {
+		t.Errorf("Unable to write the tree, %v", err)
+	}
+	lines = decodeNDJSON(t, buf.Bytes())
+	if fmt.Sprint(lines) != fmt.Sprint(expected) {
+		t.Errorf("Expected %q, got %q", expected, lines)
+	}
+}
@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 //Options Data model to hold command line options
 type Options struct {
 	IncludeHidden    bool
@@ -13,22 +15,118 @@ type Options struct {
 	PrintUID         bool
 	PrintGID         bool
 	PrintModTime     bool
+	PrintChangeTime  bool
+	PrintAccessTime  bool
+	PrintInode       bool
+	PrintDevice      bool
+	PrintLinks       bool
+	AggregateSize    bool
+	PrintBlocks      bool
+	HumanReadable    bool
+	SIUnits          bool
 	SortReverse      bool
 	SortByModTime    bool
+	SortKey          string
+	DirsFirst        bool
+	FilesFirst       bool
 	FileLimit        int
+	Jobs             int
+	Hash             string
+	HashMaxSize      int64
+	MarkDuplicates   bool
+	MinSize          int64
+	MaxSize          int64
+	NewerThan        time.Time
+	OlderThan        time.Time
+	Types            []FileType
+	Empty            bool
+	Perm             string
+	OwnerFilter      string
+	GroupFilter      string
 	MaxLevel         int16
 	Indent           int
 	TimeFormat       string
-	IncludePattern   string
-	ExcludePattern   string
+	IncludePattern   []string
+	ExcludePattern   []string
+	PatternRegex     bool
+	MatchDirs        bool
 	OutputPath       string
+	HTMLBaseURL      string
+	HTMLTitle        string
+	Classify         bool
+	NumericIDs       bool
+	Columns          []string
+	Charset          string
+	MarkdownList     bool
+	MarkdownLinks    bool
 	JSONIncludeStats bool
+	UseGitIgnore     bool
 	DirColor         Colorize
 	FileColor        Colorize
 	SymLinkColor     Colorize
 	TLinkColor       Colorize
 	LLinkColor       Colorize
 	PipeColor        Colorize
+	ErrorColor       Colorize
+	ExecColor        Colorize
+	FIFOColor        Colorize
+	SocketColor      Colorize
+	DeviceColor      Colorize
+	// LSColors when set takes precedence over the colors above, for the nodes
+	// it has a rule for
+	LSColors *LSColors
+
+	// gitIgnore matcher shared across the traversal when UseGitIgnore is set
+	gitIgnore *GitIgnore
+	// patterns compiled from IncludePattern & ExcludePattern
+	patterns *patternFilter
+	// predicates compiled from the size, time, type, permission & owner options
+	predicates *predicateFilter
+	// comparator built from the sort options, nil for directory order
+	comparator Comparator
+	// widths of the extra columns computed over the tree being printed
+	widths []int
+}
+
+// prepare Returns opt with the state shared across the traversal rooted at
+// root initialized, invalid options are reported as error
+func (opt Options) prepare(root string) (Options, error) {
+	opt, err := opt.withGitIgnore(root)
+	if err != nil {
+		return opt, err
+	}
+	if opt.patterns == nil {
+		opt.patterns, err = newPatternFilter(root, opt)
+		if err != nil {
+			return opt, err
+		}
+	}
+	if err := ValidateHash(opt.Hash); err != nil {
+		return opt, err
+	}
+	if opt.predicates == nil {
+		if opt.predicates, err = newPredicateFilter(opt); err != nil {
+			return opt, err
+		}
+	}
+	if opt.comparator == nil {
+		opt.comparator, err = NewComparator(opt)
+	}
+	return opt, err
+}
+
+// withGitIgnore Returns opt with gitignore matcher initialized for traversal
+// rooted at root, if UseGitIgnore is set and it is not initialized yet
+func (opt Options) withGitIgnore(root string) (Options, error) {
+	if !opt.UseGitIgnore || opt.gitIgnore != nil {
+		return opt, nil
+	}
+	g, err := NewGitIgnore(root)
+	if err != nil {
+		return opt, err
+	}
+	opt.gitIgnore = g
+	return opt, nil
 }
 
 // DefaultOptions A utility method to create default Options for hitree command
@@ -43,14 +141,21 @@ func DefaultOptions() Options {
 		Prune:          false,
 		MaxLevel:       -1,
 		FileLimit:      -1,
-		IncludePattern: "",
-		ExcludePattern: "",
+		Jobs:           1,
+		HashMaxSize:    -1,
+		MinSize:        -1,
+		MaxSize:        -1,
 		DirColor:       ColorMap["gray"],
 		FileColor:      ColorMap["gray"],
 		SymLinkColor:   ColorMap["gray"],
 		TLinkColor:     ColorMap["gray"],
 		LLinkColor:     ColorMap["gray"],
 		PipeColor:      ColorMap["gray"],
+		ErrorColor:     ColorMap["gray"],
+		ExecColor:      ColorMap["gray"],
+		FIFOColor:      ColorMap["gray"],
+		SocketColor:    ColorMap["gray"],
+		DeviceColor:    ColorMap["gray"],
 	}
 	return opt
 }
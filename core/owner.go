@@ -0,0 +1,76 @@
+package core
+
+import (
+	"os"
+	"os/user"
+	"sync"
+)
+
+// ownerNames Cache of user & group names by numeric id, since a lookup may
+// read /etc/passwd or query a directory service for every node
+var ownerNames = struct {
+	sync.Mutex
+	users  map[string]string
+	groups map[string]string
+}{users: map[string]string{}, groups: map[string]string{}}
+
+// userName Name of the user with the given id, the id itself if it is unknown
+func userName(uid string) string {
+	ownerNames.Lock()
+	defer ownerNames.Unlock()
+	name, ok := ownerNames.users[uid]
+	if !ok {
+		name = uid
+		if u, err := user.LookupId(uid); err == nil {
+			name = u.Username
+		}
+		ownerNames.users[uid] = name
+	}
+	return name
+}
+
+// groupName Name of the group with the given id, the id itself if it is
+// unknown
+func groupName(gid string) string {
+	ownerNames.Lock()
+	defer ownerNames.Unlock()
+	name, ok := ownerNames.groups[gid]
+	if !ok {
+		name = gid
+		if g, err := user.LookupGroupId(gid); err == nil {
+			name = g.Name
+		}
+		ownerNames.groups[gid] = name
+	}
+	return name
+}
+
+// setOwner Fill the owner of fi in stats, both as numeric ids and names
+func (stats *Stats) setOwner(fi os.FileInfo) {
+	uid, gid, ok := fileOwner(fi)
+	if !ok {
+		return
+	}
+	stats.UID, stats.GID = uid, gid
+	stats.Owner, stats.Group = userName(uid), groupName(gid)
+}
+
+// ownerOf User owning the node as per options, "-" when it is not known
+func ownerOf(stats Stats, opt Options) string {
+	return idOrName(stats.UID, stats.Owner, opt)
+}
+
+// groupOf Group owning the node as per options, "-" when it is not known
+func groupOf(stats Stats, opt Options) string {
+	return idOrName(stats.GID, stats.Group, opt)
+}
+
+func idOrName(id, name string, opt Options) string {
+	switch {
+	case opt.NumericIDs && id != "":
+		return id
+	case name != "":
+		return name
+	}
+	return "-"
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// VirtualFileInfo An os.FileInfo which is not backed by the file system. It is
+// used for nodes of trees built from a list of paths or from a JSON snapshot,
+// where the entries may not exist on this machine.
+type VirtualFileInfo struct {
+	FName    string
+	FSize    int64
+	FMode    os.FileMode
+	FModTime time.Time
+}
+
+// Name base name of the file
+func (fi VirtualFileInfo) Name() string { return fi.FName }
+
+// Size length in bytes for regular files
+func (fi VirtualFileInfo) Size() int64 { return fi.FSize }
+
+// Mode file mode bits
+func (fi VirtualFileInfo) Mode() os.FileMode { return fi.FMode }
+
+// ModTime modification time
+func (fi VirtualFileInfo) ModTime() time.Time { return fi.FModTime }
+
+// IsDir abbreviation for Mode().IsDir()
+func (fi VirtualFileInfo) IsDir() bool { return fi.FMode.IsDir() }
+
+// Sys underlying data source, always nil
+func (fi VirtualFileInfo) Sys() interface{} { return nil }
+
+// pathNode Intermediate node used while building tree from paths
+type pathNode struct {
+	fi       VirtualFileInfo
+	children map[string]*pathNode
+}
+
+func (node *pathNode) child(name string) *pathNode {
+	node.markDir()
+	c, ok := node.children[name]
+	if !ok {
+		c = &pathNode{fi: VirtualFileInfo{FName: name, FMode: 0644}}
+		node.children[name] = c
+	}
+	return c
+}
+
+func (node *pathNode) markDir() {
+	if node.children == nil {
+		node.children = map[string]*pathNode{}
+		node.fi.FMode = os.ModeDir | 0755
+	}
+}
+
+// ReadPaths Read list of paths from r, separated by NUL if the input contains
+// any NUL byte (like find -print0 or git ls-files -z), otherwise by newline.
+func ReadPaths(r io.Reader) ([]string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	sep := []byte("\n")
+	if bytes.IndexByte(data, 0) >= 0 {
+		sep = []byte{0}
+	}
+	paths := make([]string, 0)
+	for _, line := range bytes.Split(data, sep) {
+		p := strings.TrimRight(string(line), "\r")
+		if strings.TrimSpace(p) != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+// TreeFromPaths Build a Tree named root from a list of slash separated paths,
+// without touching the file system. Every path which is a prefix of another
+// path, or which ends with a slash, is a directory, everything else is a file.
+// Filtering, sorting, level & dironly options are applied like in TraverseDir.
+func TreeFromPaths(root string, paths []string, opt Options) (Tree, error) {
+	top := &pathNode{fi: VirtualFileInfo{FName: root, FMode: os.ModeDir | 0755}}
+	for _, p := range paths {
+		isDir := strings.HasSuffix(p, "/")
+		node := top
+		for _, part := range strings.Split(path.Clean("/"+p), "/") {
+			if part == "" {
+				continue
+			}
+			node = node.child(part)
+		}
+		if isDir {
+			node.markDir()
+		}
+	}
+	return top.toTree().Reshape(opt)
+}
+
+// toTree Convert the node into Tree with children sorted by name
+func (node *pathNode) toTree() Tree {
+	childrens := make([]Tree, 0, len(node.children))
+	for _, c := range node.children {
+		childrens = append(childrens, c.toTree())
+	}
+	sort.Slice(childrens, func(i, j int) bool {
+		return childrens[i].Root.Name() < childrens[j].Root.Name()
+	})
+	return Tree{Root: node.fi, Childrens: childrens, Stats: NewEmptyStats(node.fi)}
+}
+
+// Reshape Rebuild the tree applying filtering, sorting, level & dironly options
+// like TraverseDir does, recomputing the counts in Stats. It is useful for trees
+// which are not read from the file system, like the ones built from paths or
+// loaded from JSON. Entries dropped from the tree earlier can not be restored.
+// UseGitIgnore is ignored, the tree has no place in the file system.
+func (tree Tree) Reshape(opt Options) (Tree, error) {
+	// .gitignore files of the working directory don't apply to the tree
+	opt.UseGitIgnore, opt.gitIgnore = false, nil
+	opt, err := opt.prepare(".")
+	if err != nil {
+		return Tree{}, err
+	}
+	return tree.reshape(".", opt, 0), nil
+}
+
+// reshape Helper private method for Reshape, dir is the path of the tree
+// relative to the root
+func (tree Tree) reshape(dir string, opt Options, level int16) Tree {
+	stats := tree.Stats
+	stats.DirCount, stats.FileCount, stats.ErrorCount = 0, 0, 0
+	if tree.Err != nil {
+		stats.ErrorCount++
+	}
+	childrens := make([]Tree, 0)
+	if !tree.Root.IsDir() || (opt.MaxLevel > -1 && level >= opt.MaxLevel) {
+		return Tree{Root: tree.Root, Childrens: childrens, Stats: stats, Err: tree.Err, Link: tree.Link, DuplicateOf: tree.DuplicateOf}
+	}
+	files := make([]os.FileInfo, len(tree.Childrens))
+	byName := make(map[string]Tree, len(tree.Childrens))
+	for i, c := range tree.Childrens {
+		files[i] = c.Root
+		byName[c.Root.Name()] = c
+	}
+	for _, fi := range arrangeEntries(dir, files, opt) {
+		original := byName[fi.Name()]
+		subtree := original.reshape(path.Join(dir, fi.Name()), opt, level+1)
+		if !opt.predicates.keep(subtree, func() bool { return len(original.Childrens) == 0 }) {
+			continue
+		}
+		stats = updateStats(subtree, stats, opt)
+		childrens = updateChildrens(subtree, childrens, opt, fi)
+	}
+	return Tree{Root: tree.Root, Childrens: childrens, Stats: stats, Err: tree.Err, Link: tree.Link, DuplicateOf: tree.DuplicateOf}
+}
@@ -0,0 +1,91 @@
+package core_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestReadPaths(t *testing.T) {
+	table := []struct {
+		input    string
+		expected []string
+	}{
+		{"a/b\na/c\r\n\n./d\n", []string{"a/b", "a/c", "./d"}},
+		{"a/b\x00a/with\nnewline\x00", []string{"a/b", "a/with\nnewline"}},
+		{"", []string{}},
+	}
+	for _, test := range table {
+		paths, err := core.ReadPaths(strings.NewReader(test.input))
+		if err != nil {
+			t.Errorf("Unable to read paths, %v", err)
+		}
+		if !reflect.DeepEqual(paths, test.expected) {
+			t.Errorf("Expected %q, got %q", test.expected, paths)
+		}
+	}
+}
+
+func TestTreeFromPaths(t *testing.T) {
+	opt := core.DefaultOptions()
+	core.InitAurora(false)
+	paths := []string{
+		"normal.go",
+		"./a/normal.py",
+		"a/c/normal.go",
+		"a/c/d/normal.py",
+		"a/b/.hidden",
+		"a/b/normal.go",
+		"a/c/d/e/",
+	}
+	tree, err := core.TreeFromPaths("root", paths, opt)
+	if err != nil {
+		t.Errorf("Unable to build tree from paths, %v", err)
+	}
+	buf := new(bytes.Buffer)
+	tree.Print(buf, opt)
+	output := `root
+├──a
+│  ├──b
+│  │  └──normal.go
+│  ├──c
+│  │  ├──d
+│  │  │  ├──e
+│  │  │  └──normal.py
+│  │  └──normal.go
+│  └──normal.py
+└──normal.go
+
+5 directories, 5 files
+`
+	if buf.String() != output {
+		t.Errorf("expected %s, got: \n%s", output, buf.String())
+	}
+
+	opt.MaxLevel = 1
+	opt.IncludePattern = []string{"*.go"}
+	tree, err = core.TreeFromPaths("root", paths, opt)
+	if err != nil {
+		t.Errorf("Unable to build tree from paths, %v", err)
+	}
+	if tree.Stats.DirCount != 1 || tree.Stats.FileCount != 1 {
+		t.Errorf("Expected 1 directory & 1 file, got %+v", tree.Stats)
+	}
+}
+
+func TestTreeFromPaths_gitIgnore(t *testing.T) {
+	// The test dir is the working directory, its .gitignore is not applied
+	// to the paths
+	cleaner, opt, _ := helper.SetupGitIgnoreTestDir(uuid.New().String())
+	defer cleaner()
+	opt.UseGitIgnore = true
+	tree, err := core.TreeFromPaths(".", []string{"a.o", "app.log", "build/x.go"}, opt)
+	if err != nil || tree.String() != ".->[a.o->[] app.log->[] build->[x.go->[]]]" {
+		t.Errorf("Expected every path to be listed, got %s, %v", tree, err)
+	}
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PatternSet A compiled list of include or exclude patterns. Patterns are either
+// wild-card globs or regular expressions.
+//
+// A glob without a slash is matched against the base name of the entry, as
+// before. A glob containing a slash is matched against the slash separated path
+// relative to the traversal root, where ** matches any number of directories
+// e.g. "**/testdata/*.json" or "cmd/**".
+//
+// A regular expression is matched (unanchored) against the relative path.
+type PatternSet struct {
+	patterns []compiledPattern
+}
+
+type compiledPattern struct {
+	re       *regexp.Regexp
+	fullPath bool
+}
+
+// NewPatternSet Compile patterns as regular expressions if regex is true,
+// otherwise as globs. Malformed patterns are reported as error.
+func NewPatternSet(patterns []string, regex bool) (*PatternSet, error) {
+	set := &PatternSet{}
+	for _, p := range patterns {
+		if p == "" {
+			continue
+		}
+		var (
+			re  *regexp.Regexp
+			err error
+		)
+		if regex {
+			re, err = regexp.Compile(p)
+		} else {
+			re, err = compileGlob(p)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", p, err)
+		}
+		set.patterns = append(set.patterns, compiledPattern{re: re, fullPath: regex || strings.Contains(p, "/")})
+	}
+	return set, nil
+}
+
+// compileGlob Validate glob with filepath.Match rules and translate it into an
+// anchored regular expression
+func compileGlob(glob string) (*regexp.Regexp, error) {
+	if _, err := filepath.Match(strings.Replace(glob, "**", "*", -1), ""); err != nil {
+		return nil, err
+	}
+	return regexp.Compile("^" + globToRegexp(strings.TrimPrefix(glob, "/")) + "$")
+}
+
+// Empty Check if there are no patterns in the set
+func (set *PatternSet) Empty() bool {
+	return set == nil || len(set.patterns) == 0
+}
+
+// Match Check whether relPath, slash separated path relative to the traversal
+// root, matches with any of the patterns in the set
+func (set *PatternSet) Match(relPath string) bool {
+	if set == nil {
+		return false
+	}
+	name := path.Base(relPath)
+	for _, p := range set.patterns {
+		if p.fullPath && p.re.MatchString(relPath) {
+			return true
+		}
+		if !p.fullPath && p.re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// patternFilter Include & exclude pattern sets compiled for a traversal
+type patternFilter struct {
+	root      string
+	include   *PatternSet
+	exclude   *PatternSet
+	matchDirs bool
+}
+
+func newPatternFilter(root string, opt Options) (*patternFilter, error) {
+	include, err := NewPatternSet(opt.IncludePattern, opt.PatternRegex)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := NewPatternSet(opt.ExcludePattern, opt.PatternRegex)
+	if err != nil {
+		return nil, err
+	}
+	if include.Empty() && exclude.Empty() {
+		return nil, nil
+	}
+	return &patternFilter{root: filepath.Clean(root), include: include, exclude: exclude, matchDirs: opt.MatchDirs}, nil
+}
+
+// relPath Slash separated path of entry name in dir relative to traversal root
+func (f *patternFilter) relPath(dir, name string) string {
+	rel, err := filepath.Rel(f.root, filepath.Clean(dir))
+	if err != nil || rel == "." {
+		return name
+	}
+	return filepath.ToSlash(filepath.Join(rel, name))
+}
+
+// insideMatchedDir Check if any ancestor directory of relPath matches include
+// patterns, in which case all of its content is listed
+func (f *patternFilter) insideMatchedDir(relPath string) bool {
+	for dir := path.Dir(relPath); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if f.include.Match(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter return entries of dir filtered by include & exclude patterns.
+// Directories are only matched when matchDirs is set, in which case a directory
+// matching include patterns gets all of its content listed.
+func (f *patternFilter) filter(dir string, fis []os.FileInfo) []os.FileInfo {
+	return Filter(fis, func(fi os.FileInfo) bool {
+		if fi.IsDir() && !f.matchDirs {
+			return true
+		}
+		rel := f.relPath(dir, fi.Name())
+		if f.exclude.Match(rel) {
+			return false
+		}
+		if f.include.Empty() || fi.IsDir() {
+			return true
+		}
+		return f.include.Match(rel) || (f.matchDirs && f.insideMatchedDir(rel))
+	})
+}
@@ -0,0 +1,41 @@
+package core
+
+import "sync"
+
+// workerPool bounds the number of goroutines TraverseDir uses to read
+// directories concurrently. A nil pool runs every task inline on the caller's
+// goroutine, which keeps the default traversal strictly sequential.
+type workerPool struct {
+	sem chan struct{}
+}
+
+// newWorkerPool returns a pool allowing jobs concurrent readers. The calling
+// goroutine counts as one of them, so jobs <= 1 yields a nil (sequential) pool.
+func newWorkerPool(jobs int) *workerPool {
+	if jobs <= 1 {
+		return nil
+	}
+	return &workerPool{sem: make(chan struct{}, jobs-1)}
+}
+
+// run executes task on a new goroutine when a slot is free, otherwise inline.
+// Falling back to inline execution instead of blocking guarantees that nested
+// directory tasks can never deadlock waiting on their own parents.
+func (p *workerPool) run(wg *sync.WaitGroup, task func()) {
+	if p != nil {
+		select {
+		case p.sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-p.sem
+					wg.Done()
+				}()
+				task()
+			}()
+			return
+		default:
+		}
+	}
+	task()
+}
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TypeLetters File types by the letter accepted as --type, like find -type
+var TypeLetters = map[string]FileType{
+	"f": FILE,
+	"d": DIR,
+	"l": LINK,
+	"p": FIFO,
+	"s": SOCKET,
+	"c": CHARDEV,
+	"b": BLOCKDEV,
+}
+
+// ParseFileTypes Convert letters of TypeLetters into file types
+func ParseFileTypes(letters []string) ([]FileType, error) {
+	types := make([]FileType, 0, len(letters))
+	for _, letter := range letters {
+		ftype, ok := TypeLetters[letter]
+		if !ok {
+			return nil, fmt.Errorf("invalid type %q, possible types are f, d, l, p, s, c & b", letter)
+		}
+		types = append(types, ftype)
+	}
+	return types, nil
+}
+
+// ageUnits Durations by the suffix accepted in ages beside the ones of
+// time.ParseDuration
+var ageUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+var ageFormat = regexp.MustCompile(`^(\d+)([dw])$`)
+
+// dateLayouts Layouts of the dates accepted as time bound, in local time
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// ParseTimeBound Parse the bound of --newer & --older, either an age like 90m,
+// 36h, 7d or 2w which is subtracted from now, or a date like 2018-10-02 or
+// 2018-10-02T15:04:05 in local time
+func ParseTimeBound(s string, now time.Time) (time.Time, error) {
+	if m := ageFormat.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return now.Add(-time.Duration(n) * ageUnits[m[2]]), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected age like 36h or 7d, or date like 2006-01-02", s)
+}
+
+// permFilter Permission bits to match, like find -perm: exactly when neither
+// all nor any is set, all of them with -mode and any of them with /mode
+type permFilter struct {
+	bits     os.FileMode
+	all, any bool
+}
+
+func parsePerm(s string) (*permFilter, error) {
+	f := &permFilter{}
+	value := s
+	switch {
+	case strings.HasPrefix(value, "-"):
+		f.all, value = true, value[1:]
+	case strings.HasPrefix(value, "/"):
+		f.any, value = true, value[1:]
+	}
+	bits, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || bits > 0777 {
+		return nil, fmt.Errorf("invalid permission %q, expected octal mode like 644, -644 or /111", s)
+	}
+	f.bits = os.FileMode(bits)
+	return f, nil
+}
+
+func (f *permFilter) match(perm os.FileMode) bool {
+	switch {
+	case f.all:
+		return perm&f.bits == f.bits
+	case f.any:
+		return f.bits == 0 || perm&f.bits != 0
+	}
+	return perm == f.bits
+}
+
+// predicateFilter Predicates on the metadata of the entries compiled for a
+// traversal, all of them have to match
+type predicateFilter struct {
+	minSize, maxSize int64
+	newer, older     time.Time
+	types            map[FileType]bool
+	empty            bool
+	perm             *permFilter
+	user, group      string
+}
+
+// newPredicateFilter Compile the predicates of options, nil if there is none.
+// Zero values disable a predicate, except for the sizes which are disabled
+// when negative, so MaxSize of 0 matches the empty files.
+func newPredicateFilter(opt Options) (*predicateFilter, error) {
+	f := &predicateFilter{
+		minSize: opt.MinSize,
+		maxSize: opt.MaxSize,
+		newer:   opt.NewerThan,
+		older:   opt.OlderThan,
+		empty:   opt.Empty,
+		user:    opt.OwnerFilter,
+		group:   opt.GroupFilter,
+	}
+	if opt.Perm != "" {
+		perm, err := parsePerm(opt.Perm)
+		if err != nil {
+			return nil, err
+		}
+		f.perm = perm
+	}
+	if len(opt.Types) > 0 {
+		f.types = make(map[FileType]bool, len(opt.Types))
+		for _, ftype := range opt.Types {
+			f.types[ftype] = true
+		}
+	}
+	if f.minSize < 0 && f.maxSize < 0 && f.newer.IsZero() && f.older.IsZero() && f.types == nil &&
+		!f.empty && f.perm == nil && f.user == "" && f.group == "" {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// HasPredicates Check if options filter the entries by their metadata, in
+// which case the whole tree is needed to leave out the directories without
+// matching entries
+func (opt Options) HasPredicates() bool {
+	f, err := newPredicateFilter(opt)
+	return f != nil || err != nil
+}
+
+// match Check the entry against every predicate. Emptiness of a directory
+// depends on its content, which is told by emptyDir.
+func (f *predicateFilter) match(fi os.FileInfo, emptyDir bool) bool {
+	ftype := GetFileType(fi)
+	if f.types != nil && !f.types[ftype] {
+		return false
+	}
+	if f.minSize >= 0 || f.maxSize >= 0 {
+		// Size of directory depends on the file system, not on its content,
+		// so directories are only listed for the files matching below them
+		if ftype == DIR || (f.minSize >= 0 && fi.Size() < f.minSize) || (f.maxSize >= 0 && fi.Size() > f.maxSize) {
+			return false
+		}
+	}
+	if (!f.newer.IsZero() && !fi.ModTime().After(f.newer)) || (!f.older.IsZero() && !fi.ModTime().Before(f.older)) {
+		return false
+	}
+	if f.empty && !((ftype == FILE && fi.Size() == 0) || (ftype == DIR && emptyDir)) {
+		return false
+	}
+	if f.perm != nil && !f.perm.match(fi.Mode().Perm()) {
+		return false
+	}
+	if f.user != "" || f.group != "" {
+		uid, gid, ok := fileOwner(fi)
+		if !ok {
+			return false
+		}
+		if f.user != "" && f.user != uid && f.user != userName(uid) {
+			return false
+		}
+		if f.group != "" && f.group != gid && f.group != groupName(gid) {
+			return false
+		}
+	}
+	return true
+}
+
+// filter return entries of dir matching the predicates. Directories are kept
+// whatever their metadata, to be walked for matching content.
+func (f *predicateFilter) filter(fis []os.FileInfo) []os.FileInfo {
+	return Filter(fis, func(fi os.FileInfo) bool {
+		return fi.IsDir() || f.match(fi, false)
+	})
+}
+
+// keep Check if subtree, a child of the tree being built, should be listed.
+// A directory is listed when it matches the predicates itself, or when there
+// is something to list below it, so directories left empty by the predicates
+// are pruned. isEmpty tells if the directory has no entries at all.
+func (f *predicateFilter) keep(subtree Tree, isEmpty func() bool) bool {
+	if f == nil || !subtree.Root.IsDir() || subtree.Stats.DirCount+subtree.Stats.FileCount > 0 || subtree.Err != nil {
+		return true
+	}
+	return f.match(subtree.Root, f.empty && isEmpty())
+}
+
+// isEmptyDir Check if the directory at path has no entries at all
+func isEmptyDir(path string) bool {
+	d, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer d.Close()
+	names, _ := d.Readdirnames(1)
+	return len(names) == 0
+}
@@ -0,0 +1,129 @@
+package core_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestParseSize(t *testing.T) {
+	sizes := map[string]int64{"0": 0, "512": 512, "4K": 4096, "4kb": 4096, "1.5M": 3 << 19, "2GiB": 2 << 30}
+	for s, expected := range sizes {
+		if got, err := core.ParseSize(s); err != nil || got != expected {
+			t.Errorf("Expected %d for %q, got %d, %v", expected, s, got, err)
+		}
+	}
+	for _, s := range []string{"", "K", "-1", "4X", "1..5M"} {
+		if _, err := core.ParseSize(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2018, 10, 2, 12, 0, 0, 0, time.Local)
+	bounds := map[string]time.Time{
+		"90m":                 now.Add(-90 * time.Minute),
+		"36h":                 now.Add(-36 * time.Hour),
+		"7d":                  now.AddDate(0, 0, -7),
+		"2w":                  now.AddDate(0, 0, -14),
+		"2018-09-01":          time.Date(2018, 9, 1, 0, 0, 0, 0, time.Local),
+		"2018-09-01T10:30:00": time.Date(2018, 9, 1, 10, 30, 0, 0, time.Local),
+	}
+	for s, expected := range bounds {
+		if got, err := core.ParseTimeBound(s, now); err != nil || !got.Equal(expected) {
+			t.Errorf("Expected %s for %q, got %s, %v", expected, s, got, err)
+		}
+	}
+	if _, err := core.ParseTimeBound("yesterday", now); err == nil {
+		t.Errorf("Expected error for invalid time")
+	}
+}
+
+// predicateTree Traverse the test dir with the options changed by set
+func predicateTree(t *testing.T, set func(opt *core.Options)) core.Tree {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	writeFiles(t, root, map[string]string{"normal.go": "package main\n", "a/c/normal.go": "package c\n"})
+	old := time.Now().AddDate(0, 0, -30)
+	os.Chtimes(filepath.Join(root, "a", "c", "normal.go"), old, old)
+	os.Chmod(filepath.Join(root, "a", "normal.py"), 0755)
+	set(&opt)
+	tree, err := core.TraverseDir(root, opt, -1)
+	if err != nil {
+		t.Fatalf("Unable to traverse the Dir, %v", err)
+	}
+	return tree
+}
+
+func TestTraverseDir_predicates(t *testing.T) {
+	// The test dir has normal.go, a/normal.py, a/b/normal.go, a/c/normal.go
+	// a/c/d/normal.py & the empty directory a/c/d/e
+	cases := []struct {
+		name         string
+		set          func(opt *core.Options)
+		dirs, files  int
+		expectedTree string
+	}{
+		{"min size", func(opt *core.Options) { opt.MinSize = 1 }, 2, 2, "->[a->[c->[normal.go->[]]] normal.go->[]]"},
+		{"max size", func(opt *core.Options) { opt.MaxSize = 11 }, 4, 4, "->[a->[b->[normal.go->[]] c->[d->[normal.py->[]] normal.go->[]] normal.py->[]]]"},
+		{"max size 0", func(opt *core.Options) { opt.MaxSize = 0 }, 4, 3, "->[a->[b->[normal.go->[]] c->[d->[normal.py->[]]] normal.py->[]]]"},
+		{"type", func(opt *core.Options) { opt.Types = []core.FileType{core.DIR} }, 5, 0, "->[a->[b->[] c->[d->[e->[]]]]]"},
+		{"empty", func(opt *core.Options) { opt.Empty = true }, 5, 3, "->[a->[b->[normal.go->[]] c->[d->[e->[] normal.py->[]]] normal.py->[]]]"},
+		{"newer", func(opt *core.Options) {
+			opt.NewerThan = time.Now().AddDate(0, 0, -1)
+			opt.Types = []core.FileType{core.FILE}
+		}, 4, 4,
+			"->[a->[b->[normal.go->[]] c->[d->[normal.py->[]]] normal.py->[]] normal.go->[]]"},
+		{"older", func(opt *core.Options) { opt.OlderThan = time.Now().AddDate(0, 0, -1) }, 2, 1, "->[a->[c->[normal.go->[]]]]"},
+		{"perm", func(opt *core.Options) { opt.Perm = "-100"; opt.Types = []core.FileType{core.FILE} }, 1, 1, "->[a->[normal.py->[]]]"},
+		{"all together", func(opt *core.Options) { opt.MinSize, opt.OlderThan = 1, time.Now() }, 2, 2, "->[a->[c->[normal.go->[]]] normal.go->[]]"},
+	}
+	for _, c := range cases {
+		tree := predicateTree(t, c.set)
+		tree.Root = core.VirtualFileInfo{FMode: os.ModeDir}
+		if got := tree.String(); got != c.expectedTree {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expectedTree, got)
+		}
+		if tree.Stats.DirCount != c.dirs || tree.Stats.FileCount != c.files {
+			t.Errorf("%s: expected %d directories & %d files, got %+v", c.name, c.dirs, c.files, tree.Stats)
+		}
+	}
+
+	// Trees which are not read from the file system are filtered the same way
+	opt := core.DefaultOptions()
+	opt.Types = []core.FileType{core.FILE}
+	tree, err := core.TreeFromPaths(".", []string{"a/b/main.go", "a/empty/", "c/"}, opt)
+	if err != nil || tree.String() != ".->[a->[b->[main.go->[]]]]" {
+		t.Errorf("Expected directories without files to be left out, got %s, %v", tree, err)
+	}
+
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	opt.Perm = "8"
+	if _, err := core.TraverseDir(root, opt, -1); err == nil {
+		t.Errorf("Expected invalid permission to be reported")
+	}
+}
+
+func TestTraverseDir_ownerPredicate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Ownership is not available on windows")
+	}
+	uid := os.Getuid()
+	mine := predicateTree(t, func(opt *core.Options) { opt.OwnerFilter = fmt.Sprint(uid) })
+	if mine.Stats.FileCount != 5 {
+		t.Errorf("Expected all files to be owned by the current user, got %+v", mine.Stats)
+	}
+	others := predicateTree(t, func(opt *core.Options) { opt.OwnerFilter = fmt.Sprint(uid + 1) })
+	if others.Stats.FileCount != 0 || others.Stats.DirCount != 0 {
+		t.Errorf("Expected nothing owned by an other user, got %+v", others.Stats)
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path"
+)
+
+// nodeRecord A node along with its location in the tree, used by the flat
+// output formats which write one record per node
+type nodeRecord struct {
+	path   string
+	parent string
+	depth  int
+	fi     os.FileInfo
+	stats  Stats
+	err    error
+	link   *Link
+}
+
+// recordVisitor Callback invoked for every record, in the order they are listed
+type recordVisitor func(record nodeRecord) error
+
+// streamRecords Walk the directory rooted at root and invoke visit for every
+// node as soon as it is discovered. Paths are built from the name of the root
+// like walkRecords does. opt.Prune, opt.AggregateSize and opt.Jobs are ignored.
+func streamRecords(root string, opt Options, visit recordVisitor) (Stats, error) {
+	opt.AggregateSize = false
+	// paths of the ancestors of the entry being visited, by depth
+	paths := make([]string, 0)
+	return streamWalk(context.Background(), root, opt, func(entry streamEntry) error {
+		if entry.fi == nil {
+			return entry.err
+		}
+		record := nodeRecord{depth: entry.depth, fi: entry.fi, stats: nodeStats(entry.fi, opt), err: entry.err, link: entry.link}
+		paths = paths[:entry.depth]
+		if entry.depth == 0 {
+			name, err := Tree{Root: entry.fi}.NodeName(opt)
+			if err != nil {
+				return err
+			}
+			record.path = name
+		} else {
+			record.parent = paths[entry.depth-1]
+			record.path = path.Join(record.parent, entry.fi.Name())
+		}
+		paths = append(paths, record.path)
+		return visit(record)
+	})
+}
+
+// walkRecords Invoke visit for every node of the tree in the order they are
+// printed. Paths are built from the name of the root.
+func (tree Tree) walkRecords(opt Options, visit recordVisitor) error {
+	name, err := tree.NodeName(opt)
+	if err != nil {
+		return err
+	}
+	return tree.walkRecord(name, "", 0, opt, visit)
+}
+
+func (tree Tree) walkRecord(nodePath, parent string, depth int, opt Options, visit recordVisitor) error {
+	if err := visit(nodeRecord{path: nodePath, parent: parent, depth: depth, fi: tree.Root, stats: tree.Stats, err: tree.Err, link: tree.Link}); err != nil {
+		return err
+	}
+	for _, subtree := range tree.Childrens {
+		if canPrune(subtree, opt) {
+			continue
+		}
+		childPath := path.Join(nodePath, subtree.Root.Name())
+		if err := subtree.walkRecord(childPath, nodePath, depth+1, opt, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Renderer Writes a tree in one output format
+type Renderer interface {
+	Render(w io.Writer, tree Tree, opt Options) error
+}
+
+// StreamRenderer A Renderer which can also write the nodes of a directory
+// while it is walked, without building the Tree in memory first
+type StreamRenderer interface {
+	Renderer
+	Stream(w io.Writer, root string, opt Options) (Stats, error)
+}
+
+// RendererFunc Adapter to use an ordinary function as Renderer
+type RendererFunc func(w io.Writer, tree Tree, opt Options) error
+
+// Render calls f(w, tree, opt)
+func (f RendererFunc) Render(w io.Writer, tree Tree, opt Options) error {
+	return f(w, tree, opt)
+}
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]Renderer{}
+)
+
+// RegisterRenderer Make the renderer available by name, e.g. for --format flag.
+// Registering a name again replaces the previous renderer, which allows to
+// override the builtin formats.
+func RegisterRenderer(name string, r Renderer) {
+	if r == nil {
+		panic("hitree: RegisterRenderer renderer is nil")
+	}
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[name] = r
+}
+
+// LookupRenderer Get the renderer registered by name
+func LookupRenderer(name string) (Renderer, error) {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	r, ok := renderers[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid format %q, possible formats are %s", name, strings.Join(rendererNames(), ", "))
+	}
+	return r, nil
+}
+
+// RendererNames Sorted names of the registered renderers
+func RendererNames() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	return rendererNames()
+}
+
+func rendererNames() []string {
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// textRenderer Renders the tree like tree command
+type textRenderer struct{}
+
+func (textRenderer) Render(w io.Writer, tree Tree, opt Options) error {
+	tree.Print(w, opt)
+	return nil
+}
+
+func (textRenderer) Stream(w io.Writer, root string, opt Options) (Stats, error) {
+	return StreamTree(w, root, opt)
+}
+
+// ndjsonRenderer Renders one JSON object per node
+type ndjsonRenderer struct{}
+
+func (ndjsonRenderer) Render(w io.Writer, tree Tree, opt Options) error {
+	return tree.WriteNDJSON(w, opt)
+}
+
+func (ndjsonRenderer) Stream(w io.Writer, root string, opt Options) (Stats, error) {
+	return StreamNDJSON(w, root, opt)
+}
+
+// tableRenderer Renders one row per node, fields separated by sep
+type tableRenderer struct {
+	sep rune
+}
+
+func (r tableRenderer) Render(w io.Writer, tree Tree, opt Options) error {
+	return tree.WriteTable(w, opt, r.sep)
+}
+
+func (r tableRenderer) Stream(w io.Writer, root string, opt Options) (Stats, error) {
+	return StreamTable(w, root, opt, r.sep)
+}
+
+// renderJSON Renders the whole tree as one indented JSON document
+func renderJSON(w io.Writer, tree Tree, opt Options) error {
+	res, err := tree.AsJSONString(opt)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "\n%s\n", res)
+	return err
+}
+
+func init() {
+	RegisterRenderer("text", textRenderer{})
+	RegisterRenderer("json", RendererFunc(renderJSON))
+	RegisterRenderer("ndjson", ndjsonRenderer{})
+	RegisterRenderer("csv", tableRenderer{sep: ','})
+	RegisterRenderer("tsv", tableRenderer{sep: '\t'})
+	RegisterRenderer("markdown", RendererFunc(func(w io.Writer, tree Tree, opt Options) error {
+		return tree.AsMarkdown(w, opt)
+	}))
+	RegisterRenderer("html", RendererFunc(func(w io.Writer, tree Tree, opt Options) error {
+		return tree.AsHTML(w, opt)
+	}))
+	RegisterRenderer("xml", RendererFunc(func(w io.Writer, tree Tree, opt Options) error {
+		return tree.AsXML(w, opt)
+	}))
+}
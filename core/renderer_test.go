@@ -0,0 +1,79 @@
+package core_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestRegisterRenderer(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	tree, _ := core.TraverseDir(root, opt, 0)
+
+	core.RegisterRenderer("count", core.RendererFunc(func(w io.Writer, tree core.Tree, opt core.Options) error {
+		_, err := fmt.Fprintf(w, "%s has %d entries\n", tree.Root.Name(), len(tree.Childrens))
+		return err
+	}))
+	defer core.UnregisterRenderer("count")
+	r, err := core.LookupRenderer("count")
+	if err != nil {
+		t.Errorf("Registered renderer not found, %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := r.Render(buf, tree, opt); err != nil {
+		t.Errorf("Unable to render, %v", err)
+	}
+	if expected := fmt.Sprintf("%s has 2 entries\n", root_file); buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	found := false
+	for _, name := range core.RendererNames() {
+		found = found || name == "count"
+	}
+	if !found {
+		t.Errorf("Expected count in renderers %v", core.RendererNames())
+	}
+	if _, err := core.LookupRenderer("yaml"); err == nil {
+		t.Errorf("Expected error for unknown format")
+	}
+}
+
+func TestBuiltinRenderers(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	tree, _ := core.TraverseDir(root, opt, 0)
+
+	// The text renderer writes the same output as Print, streamed or not
+	expected := new(bytes.Buffer)
+	tree.Print(expected, opt)
+	r, _ := core.LookupRenderer("text")
+	buf := new(bytes.Buffer)
+	r.Render(buf, tree, opt)
+	if buf.String() != expected.String() {
+		t.Errorf("Expected %s, got %s", expected, buf)
+	}
+	streamer, ok := r.(core.StreamRenderer)
+	if !ok {
+		t.Fatalf("Expected text renderer to stream")
+	}
+	buf.Reset()
+	streamer.Stream(buf, root, opt)
+	if lines := bytes.Count(buf.Bytes(), []byte("\n")); lines != bytes.Count(expected.Bytes(), []byte("\n")) {
+		t.Errorf("Expected %s, got %s", expected, buf)
+	}
+	for _, name := range []string{"json", "markdown", "html", "xml"} {
+		r, _ := core.LookupRenderer(name)
+		if _, ok := r.(core.StreamRenderer); ok {
+			t.Errorf("Expected %s renderer not to stream", name)
+		}
+	}
+}
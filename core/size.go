@@ -0,0 +1,39 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sizeUnits Multiplier by the suffix accepted in sizes, in powers of 1024
+// like the units printed with --human
+var sizeUnits = map[string]int64{
+	"":  1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+	"P": 1 << 50,
+}
+
+// ParseSize Parse size in bytes, optionally followed by one of the units K, M,
+// G, T or P in powers of 1024, like 512, 4K or 1.5G. A trailing B or iB is
+// accepted too, so 10MB & 10MiB are the same as 10M.
+func ParseSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "B"), "I")
+	i := len(value)
+	for i > 0 && !isDigit(value[i-1]) {
+		i--
+	}
+	unit, ok := sizeUnits[value[i:]]
+	if !ok || i == 0 {
+		return 0, fmt.Errorf("invalid size %q, expected bytes or a number with unit K, M, G, T or P", s)
+	}
+	n, err := strconv.ParseFloat(value[:i], 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q, expected bytes or a number with unit K, M, G, T or P", s)
+	}
+	return int64(n * float64(unit)), nil
+}
@@ -0,0 +1,22 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package core
+
+import "os"
+
+// diskUsage Block count is not available on this platform, so apparent size
+// is reported
+func diskUsage(fi os.FileInfo) int64 {
+	return fi.Size()
+}
+
+// fileOwner Ownership by numeric ids is not available on this platform
+func fileOwner(fi os.FileInfo) (uid, gid string, ok bool) {
+	return "", "", false
+}
+
+// fileIdentity Inodes are not available on this platform
+func fileIdentity(fi os.FileInfo) (inode, links, device uint64, ok bool) {
+	return 0, 0, 0, false
+}
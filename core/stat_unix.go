@@ -0,0 +1,38 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package core
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// diskUsage Bytes allocated on disk for fi, derived from its 512 byte blocks
+func diskUsage(fi os.FileInfo) int64 {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return int64(st.Blocks) * 512
+	}
+	return fi.Size()
+}
+
+// fileOwner Numeric user & group ids owning fi, ok is false when fi is not
+// backed by the file system
+func fileOwner(fi os.FileInfo) (uid, gid string, ok bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", "", false
+	}
+	return strconv.FormatUint(uint64(st.Uid), 10), strconv.FormatUint(uint64(st.Gid), 10), true
+}
+
+// fileIdentity Inode number, count of hard links and id of the device holding
+// fi, ok is false when fi is not backed by the file system
+func fileIdentity(fi os.FileInfo) (inode, links, device uint64, ok bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	return uint64(st.Ino), uint64(st.Nlink), uint64(st.Dev), true
+}
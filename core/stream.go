@@ -0,0 +1,167 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+)
+
+// streamEntry A node discovered by streamWalk
+type streamEntry struct {
+	path   string
+	parent string
+	depth  int
+	fi     os.FileInfo
+	// last records for the node and each of its ancestors below the root
+	// whether it is the last visible entry of its directory
+	last []bool
+	// err is set when the node could not be stat'ed, or when it is a directory
+	// whose content could not be read
+	err error
+	// link is set when the node is a symbolic link
+	link *Link
+	// ancestors directories above the node, used to detect loops of links
+	ancestors []os.FileInfo
+}
+
+// streamVisitor Callback invoked by streamWalk for every visible node, in the
+// order they are listed. Returning SkipDir for a directory skips its content,
+// any other error aborts the walk.
+type streamVisitor func(entry streamEntry) error
+
+// streamWalk Walk the directory rooted at root, invoking visit for every node
+// as soon as it is discovered. Counts of directories & files are computed
+// incrementally and returned once the walk is complete. Errors reading a node
+// are handed to visit on the entry, the walk only aborts if visit returns an
+// error or ctx is done. Digests & duplicates need the whole tree, so Hash and
+// MarkDuplicates are rejected.
+func streamWalk(ctx context.Context, root string, opt Options, visit streamVisitor) (Stats, error) {
+	if opt.Hash != "" || opt.MarkDuplicates {
+		return Stats{}, fmt.Errorf("digests & duplicates are not available while streaming, use TraverseDir")
+	}
+	opt, err := opt.prepare(root)
+	if err != nil {
+		return Stats{}, err
+	}
+	fi, link, err := statNode(root, opt)
+	if err != nil {
+		if err := visit(streamEntry{path: root, err: err}); err != nil && err != SkipDir {
+			return Stats{}, err
+		}
+		return Stats{}, nil
+	}
+	return streamNode(ctx, streamEntry{path: root, fi: fi, link: link}, opt, 0, nodeStats(fi, opt), visit)
+}
+
+//streamNode Helper private method to visit the entry, and then recursively the
+//content of it when it is a directory. The directory is read before it is
+//visited so that a failure is reported on its own entry.
+func streamNode(ctx context.Context, entry streamEntry, opt Options, level int16, stats Stats, visit streamVisitor) (Stats, error) {
+	if err := ctx.Err(); err != nil {
+		return stats, err
+	}
+	var files []os.FileInfo
+	if entry.link != nil && entry.fi.IsDir() && isRecursive(entry.fi, entry.ancestors) {
+		entry.link.Recursive = true
+	} else if entry.err == nil && entry.fi.IsDir() {
+		files, _, entry.err = readDir(entry.path, opt, level)
+	}
+	if level == 0 || !opt.DirOnly || entry.fi.IsDir() {
+		if err := visit(entry); err == SkipDir {
+			return stats, nil
+		} else if err != nil {
+			return stats, err
+		}
+	}
+	if entry.err != nil {
+		stats.ErrorCount++
+	}
+
+	// Index of the last entry which will be visited, needed to pick the branch
+	lastIndex := len(files) - 1
+	for opt.DirOnly && lastIndex >= 0 && !isStreamedDir(files[lastIndex], entry.path, opt) {
+		lastIndex--
+	}
+
+	for i, fi := range files {
+		childPath := path.Join(entry.path, fi.Name())
+		child := streamEntry{path: childPath, parent: entry.path, depth: int(level) + 1, fi: fi}
+		child.last = append(append([]bool{}, entry.last...), i >= lastIndex)
+		child.ancestors = withAncestor(entry.ancestors, entry.fi)
+		if cfi, link, err := statNode(childPath, opt); err != nil {
+			child.err = err
+		} else {
+			child.fi, child.link = cfi, link
+		}
+		if child.fi.IsDir() {
+			stats.DirCount++
+		} else {
+			stats.FileCount++
+		}
+		var err error
+		if stats, err = streamNode(ctx, child, opt, level+1, stats, visit); err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+//isStreamedDir reports whether the entry will be listed as a directory, which
+//depends on FollowLink when the entry is a symlink
+func isStreamedDir(fi os.FileInfo, root string, opt Options) bool {
+	cfi, _, err := statNode(path.Join(root, fi.Name()), opt)
+	if err != nil {
+		return fi.IsDir()
+	}
+	return cfi.IsDir()
+}
+
+// StreamTree walks the directory rooted at root and writes every node to w as
+// soon as it is discovered, instead of building the whole Tree in memory first
+// like TraverseDir followed by Tree.Print does. The report line is computed
+// incrementally and written once the walk is complete.
+//
+// Since a directory is written before its content is known, StreamTree can not
+// prune empty directories or aggregate their sizes; opt.Prune, opt.AggregateSize
+// and opt.Jobs are ignored, and directories are written even when none of
+// their entries match the predicates. An error is returned when opt.Hash or
+// opt.MarkDuplicates is set.
+func StreamTree(w io.Writer, root string, opt Options) (Stats, error) {
+	opt.AggregateSize = false
+	stats, err := streamWalk(context.Background(), root, opt, func(entry streamEntry) error {
+		if entry.fi == nil {
+			return entry.err
+		}
+		fmt.Fprintf(w, "%s", streamPrefix(entry.last, opt))
+		Tree{Root: entry.fi, Stats: nodeStats(entry.fi, opt), Err: entry.err, Link: entry.link}.printNode(w, opt)
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+	if !opt.NoReport {
+		fmt.Fprintf(w, "\n%s\n", report(stats, opt))
+	}
+	return stats, nil
+}
+
+//streamPrefix Helper private method to build the colorized branches of a node
+//from the lastness of the node and its ancestors
+func streamPrefix(last []bool, opt Options) string {
+	prefix, cs := "", opt.charset()
+	for i, isLast := range last {
+		switch {
+		case i == len(last)-1 && isLast:
+			prefix += fmt.Sprintf("%s", opt.LLinkColor(cs.Last))
+		case i == len(last)-1:
+			prefix += fmt.Sprintf("%s", opt.TLinkColor(cs.Tee))
+		case isLast:
+			prefix += "   "
+		default:
+			prefix += fmt.Sprintf("%s  ", opt.PipeColor(cs.Pipe))
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,72 @@
+package core_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestStreamTreeMatchesPrint(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	for _, level := range []int16{-1, 1, 2} {
+		opt.MaxLevel = level
+		tree, err := core.TraverseDir(root, opt, 0)
+		if err != nil {
+			t.Errorf("Unable to traverse the Dir")
+		}
+		expected := new(bytes.Buffer)
+		tree.Print(expected, opt)
+
+		buf := new(bytes.Buffer)
+		stats, err := core.StreamTree(buf, root, opt)
+		if err != nil {
+			t.Errorf("Unable to stream the Dir, %v", err)
+		}
+		if !bytes.Equal(expected.Bytes(), buf.Bytes()) {
+			t.Errorf("expected %s, got: \n%s", expected.Bytes(), buf.Bytes())
+		}
+		if stats.DirCount != tree.Stats.DirCount || stats.FileCount != tree.Stats.FileCount {
+			t.Errorf("Expected stats %+v, got %+v", tree.Stats, stats)
+		}
+	}
+
+	// Without normal.py, c is the last child of a, so no branch of a is drawn
+	// in front of the entries below c
+	opt.MaxLevel = -1
+	opt.IncludePattern = []string{"*.go"}
+	tree, _ := core.TraverseDir(root, opt, 0)
+	expected, buf := new(bytes.Buffer), new(bytes.Buffer)
+	tree.Print(expected, opt)
+	core.StreamTree(buf, root, opt)
+	if !bytes.Equal(expected.Bytes(), buf.Bytes()) || !bytes.Contains(buf.Bytes(), []byte("\n│     │  └──e")) {
+		t.Errorf("expected %s, got: \n%s", expected.Bytes(), buf.Bytes())
+	}
+}
+
+func TestStreamTree_dirOnly(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	opt.DirOnly = true
+	buf := new(bytes.Buffer)
+	if _, err := core.StreamTree(buf, root, opt); err != nil {
+		t.Errorf("Unable to stream the Dir, %v", err)
+	}
+	output := []byte(fmt.Sprintf(`%s
+└──a
+   ├──b
+   └──c
+      └──d
+         └──e
+
+5 directories, 5 files
+`, root_file))
+	if !bytes.Equal(output, buf.Bytes()) {
+		t.Errorf("expected %s, got: \n%s", output, buf.Bytes())
+	}
+}
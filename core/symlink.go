@@ -0,0 +1,59 @@
+package core
+
+import "os"
+
+// Link Details of a node which is a symbolic link
+type Link struct {
+	// Target destination of the link, as stored in the link
+	Target string
+	// Dangling is set when the target does not exist
+	Dangling bool
+	// Recursive is set when the link was followed to a directory which is
+	// already being listed above it, its content is not listed again
+	Recursive bool
+}
+
+// statNode Stat the node at path, following symbolic links when
+// opt.FollowLink is set. link is nil unless the node is a symbolic link, a
+// dangling link is not an error and is returned with its own info. Other
+// failures to resolve the target (e.g. a loop of links) are only reported when
+// the link has to be followed.
+func statNode(path string, opt Options) (os.FileInfo, *Link, error) {
+	lfi, err := os.Lstat(path)
+	if err != nil || lfi.Mode()&os.ModeSymlink == 0 {
+		return lfi, nil, err
+	}
+	target, err := os.Readlink(path)
+	if err != nil {
+		return lfi, nil, err
+	}
+	link := &Link{Target: target}
+	fi, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		link.Dangling = true
+		return lfi, link, nil
+	case !opt.FollowLink:
+		return lfi, link, nil
+	case err != nil:
+		return lfi, link, err
+	}
+	return fi, link, nil
+}
+
+// isRecursive Check if fi is the same directory as one of its ancestors,
+// which are compared by device & inode
+func isRecursive(fi os.FileInfo, ancestors []os.FileInfo) bool {
+	for _, a := range ancestors {
+		if os.SameFile(fi, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// withAncestor Ancestors of the content of the directory fi, copied so that
+// siblings traversed concurrently don't share the backing array
+func withAncestor(ancestors []os.FileInfo, fi os.FileInfo) []os.FileInfo {
+	return append(append(make([]os.FileInfo, 0, len(ancestors)+1), ancestors...), fi)
+}
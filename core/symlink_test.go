@@ -0,0 +1,77 @@
+package core_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestSymlinkTarget(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	os.Symlink("normal.py", filepath.Join(root, "a", "link.py"))
+	os.Symlink("missing", filepath.Join(root, "a", "broken"))
+	opt.MaxLevel = 1
+	tree, err := core.TraverseDir(root, opt, -1)
+	if err != nil {
+		t.Fatalf("Unable to traverse the Dir, %v", err)
+	}
+	buf := new(bytes.Buffer)
+	tree.Print(buf, opt)
+	output := fmt.Sprintf(`%s
+├──a
+│  ├──b
+│  ├──broken -> missing [dangling]
+│  ├──c
+│  ├──link.py -> normal.py
+│  └──normal.py
+└──normal.go
+
+3 directories, 4 files
+`, root_file)
+	if output != buf.String() {
+		t.Errorf("expected %s, got: \n%s", output, buf.String())
+	}
+
+	// Targets survive a JSON round trip
+	data, _ := tree.AsJSONString(opt)
+	loaded, _ := core.LoadJSON(bytes.NewReader(data))
+	buf.Reset()
+	loaded.Print(buf, opt)
+	if output != buf.String() {
+		t.Errorf("expected %s, got: \n%s", output, buf.String())
+	}
+}
+
+func TestSymlinkLoop(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	os.Symlink("..", filepath.Join(root, "a", "b", "up"))
+	opt.FollowLink = true
+	tree, err := core.TraverseDir(filepath.Join(root, "a"), opt, 0)
+	if err != nil {
+		t.Fatalf("Unable to traverse the Dir, %v", err)
+	}
+	buf := new(bytes.Buffer)
+	tree.Print(buf, opt)
+	if !strings.Contains(buf.String(), "up -> .. [recursive, not followed]") {
+		t.Errorf("Expected loop to be detected, got\n%s", buf)
+	}
+
+	buf.Reset()
+	if _, err := core.StreamTree(buf, filepath.Join(root, "a"), opt); err != nil {
+		t.Fatalf("Unable to stream the Dir, %v", err)
+	}
+	if !strings.Contains(buf.String(), "up -> .. [recursive, not followed]") {
+		t.Errorf("Expected loop to be detected while streaming, got\n%s", buf)
+	}
+}
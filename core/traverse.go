@@ -1,10 +1,13 @@
 package core
 
 import (
-	"io/ioutil"
+	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 )
 
 //ByModTime Sort FileInfos by ModificationTime
@@ -21,54 +24,267 @@ func (a ByNameReverse) Len() int           { return len(a) }
 func (a ByNameReverse) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a ByNameReverse) Less(i, j int) bool { return a[i].Name() > a[j].Name() }
 
-//TraverseDir utility method to recursively traverse through the dir
+//Comparator Compare two FileInfos, returns negative if a sorts before b,
+//positive if a sorts after b and zero if they are equivalent. Comparators can
+//be composed using Then & Reverse.
+type Comparator func(a, b os.FileInfo) int
+
+//Then Composes comparator which uses next to break ties of c
+func (c Comparator) Then(next Comparator) Comparator {
+	return func(a, b os.FileInfo) int {
+		if res := c(a, b); res != 0 {
+			return res
+		}
+		return next(a, b)
+	}
+}
+
+//Reverse Composes comparator with the reverse ordering of c
+func (c Comparator) Reverse() Comparator {
+	return func(a, b os.FileInfo) int {
+		return c(b, a)
+	}
+}
+
+//SortKeys Comparators by the name of key accepted as --sort, ties are broken by name
+var SortKeys = map[string]Comparator{
+	"name":    CompareName,
+	"size":    CompareSize,
+	"mtime":   CompareModTime,
+	"ctime":   CompareChangeTime,
+	"ext":     CompareExtension,
+	"version": CompareVersion,
+}
+
+//CompareName Compare FileInfos by name
+func CompareName(a, b os.FileInfo) int {
+	return strings.Compare(a.Name(), b.Name())
+}
+
+//CompareSize Compare FileInfos by size
+func CompareSize(a, b os.FileInfo) int {
+	return compareInt64(a.Size(), b.Size())
+}
+
+//CompareModTime Compare FileInfos by modification time
+func CompareModTime(a, b os.FileInfo) int {
+	return compareInt64(a.ModTime().UnixNano(), b.ModTime().UnixNano())
+}
+
+//CompareChangeTime Compare FileInfos by status change time
+func CompareChangeTime(a, b os.FileInfo) int {
+	return compareInt64(changeTime(a).UnixNano(), changeTime(b).UnixNano())
+}
+
+//CompareExtension Compare FileInfos by extension of the name
+func CompareExtension(a, b os.FileInfo) int {
+	return strings.Compare(filepath.Ext(a.Name()), filepath.Ext(b.Name()))
+}
+
+//CompareVersion Compare FileInfos by name in natural order, where numbers
+//within the name are compared by value, so file9 sorts before file10
+func CompareVersion(a, b os.FileInfo) int {
+	return compareNatural(a.Name(), b.Name())
+}
+
+//CompareDirsFirst Compare FileInfos placing directories before files
+func CompareDirsFirst(a, b os.FileInfo) int {
+	if a.IsDir() == b.IsDir() {
+		return 0
+	}
+	if a.IsDir() {
+		return -1
+	}
+	return 1
+}
+
+func compareInt64(a, b int64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+//compareNatural Compare strings chunk by chunk, digit chunks by numeric value
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		ca, cb := leadingChunk(a), leadingChunk(b)
+		a, b = a[len(ca):], b[len(cb):]
+		if isDigit(ca[0]) && isDigit(cb[0]) {
+			na, nb := strings.TrimLeft(ca, "0"), strings.TrimLeft(cb, "0")
+			if res := compareInt64(int64(len(na)), int64(len(nb))); res != 0 {
+				return res
+			}
+			if res := strings.Compare(na, nb); res != 0 {
+				return res
+			}
+			continue
+		}
+		if res := strings.Compare(ca, cb); res != 0 {
+			return res
+		}
+	}
+	return compareInt64(int64(len(a)), int64(len(b)))
+}
+
+//leadingChunk Leading run of either digits or non digits of non empty s
+func leadingChunk(s string) string {
+	digit := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digit {
+		i++
+	}
+	return s[:i]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+//NewComparator Build comparator for the sort options, nil if entries should be
+//left in directory order (--sort=none)
+func NewComparator(opt Options) (Comparator, error) {
+	key := opt.SortKey
+	if key == "" && opt.SortByModTime {
+		key = "mtime"
+	}
+	if key == "" {
+		key = "name"
+	}
+	if key == "none" {
+		return nil, nil
+	}
+	cmp, ok := SortKeys[key]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort key %q, possible keys are name, size, mtime, ctime, ext, version & none", key)
+	}
+	if key != "name" {
+		cmp = cmp.Then(CompareName)
+	}
+	if opt.SortReverse {
+		cmp = cmp.Reverse()
+	}
+	if opt.DirsFirst {
+		cmp = Comparator(CompareDirsFirst).Then(cmp)
+	} else if opt.FilesFirst {
+		cmp = Comparator(CompareDirsFirst).Reverse().Then(cmp)
+	}
+	return cmp, nil
+}
+
+//TraverseDir utility method to recursively traverse through the dir.
+//When opt.Jobs is greater than one, sibling directories are read concurrently
+//by a bounded pool of workers; the resulting Tree and Stats are identical to
+//the sequential traversal. When opt.Hash is set, files are digested once the
+//traversal is complete, and so are the duplicates looked for when
+//opt.MarkDuplicates is set.
 func TraverseDir(root string, opt Options, level int16) (Tree, error) {
+	opt, err := opt.prepare(root)
+	if err != nil {
+		return Tree{}, err
+	}
+	tree, err := traverse(root, opt, level, newWorkerPool(opt.Jobs), nil)
+	if err == nil && opt.Hash != "" {
+		hashTree(&tree, root, opt)
+	}
+	if err == nil && opt.MarkDuplicates {
+		markDuplicates(&tree, root, opt)
+	}
+	return tree, err
+}
+
+//traverse Helper private method to recursively traverse root, ancestors are
+//the directories above it which are used to detect loops of followed links
+func traverse(root string, opt Options, level int16, pool *workerPool, ancestors []os.FileInfo) (Tree, error) {
 	var tree Tree
-	fi, err := fileStat(root, opt)
+	fi, link, err := statNode(root, opt)
 	if err != nil {
 		return tree, err
 	}
-	stats := NewEmptyStats(fi)
+	stats := nodeStats(fi, opt)
 	if !fi.IsDir() {
-		return Tree{Root: fi, Stats: stats}, nil
+		return Tree{Root: fi, Stats: stats, Link: link}, nil
+	}
+	if link != nil && isRecursive(fi, ancestors) {
+		link.Recursive = true
+		return Tree{Root: fi, Stats: stats, Link: link}, nil
 	}
-	files, err := ioutil.ReadDir(root)
+	files, read, err := readDir(root, opt, level)
 	if err != nil {
-		return tree, err
+		// Keep going like tree does, the error is reported on the node
+		stats.ErrorCount++
+		return Tree{Root: fi, Stats: stats, Err: err, Link: link}, nil
 	}
-
-	files = applyFilters(files, opt)
 	childrens := make([]Tree, 0)
 
-	if opt.SortByModTime {
-		sort.Sort(ByModTime(files))
+	//DFS of tree, subtrees are collected by index to keep the sorted order
+	subtrees := make([]Tree, len(files))
+	errs := make([]error, len(files))
+	childAncestors := withAncestor(ancestors, fi)
+	var wg sync.WaitGroup
+	for i, fi := range files {
+		i, childPath := i, path.Join(root, fi.Name())
+		pool.run(&wg, func() {
+			subtrees[i], errs[i] = traverse(childPath, opt, level+1, pool, childAncestors)
+		})
 	}
+	wg.Wait()
 
-	if opt.SortReverse {
-		sort.Sort(ByNameReverse(files))
-	}
-
-	for _, fi := range files {
-		if opt.MaxLevel > -1 && level >= opt.MaxLevel {
-			continue
+	for i, fi := range files {
+		if errs[i] != nil {
+			// Entry listed in the directory could not be stat'ed
+			subtrees[i] = Tree{Root: fi, Stats: nodeStats(fi, opt), Err: errs[i]}
+			subtrees[i].Stats.ErrorCount++
 		}
-		//DFS of tree
-		tree, err := TraverseDir(path.Join(root, fi.Name()), opt, level+1)
-		if err != nil {
-			return tree, err
+		childPath := path.Join(root, fi.Name())
+		if !opt.predicates.keep(subtrees[i], func() bool { return isEmptyDir(childPath) }) {
+			continue
 		}
-		stats = updateStats(tree, stats)
-		childrens = updateChildrens(tree, childrens, opt, fi)
+		stats = updateStats(subtrees[i], stats, opt)
+		childrens = updateChildrens(subtrees[i], childrens, opt, subtrees[i].Root)
 	}
-	tree = Tree{Root: fi, Childrens: childrens, Stats: stats}
+	tree = Tree{Root: fi, Childrens: childrens, Stats: stats, Link: link, unread: !read}
 	return tree, nil
 }
 
-func fileStat(path string, opt Options) (os.FileInfo, error) {
-	if opt.FollowLink {
-		return os.Stat(path)
+//readDir reads the entries of root which should be visited at the given level,
+//filtered and sorted as per the options. read is false when the entries are
+//left out because of MaxLevel or FileLimit.
+func readDir(root string, opt Options, level int16) (files []os.FileInfo, read bool, err error) {
+	if opt.MaxLevel > -1 && level >= opt.MaxLevel {
+		return nil, false, nil
 	}
-	return os.Lstat(path)
+	f, err := os.Open(root)
+	if err != nil {
+		return nil, false, err
+	}
+	files, err = f.Readdir(-1)
+	f.Close()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return arrangeEntries(root, files, opt), !opt.overFileLimit(len(files)), nil
+}
+
+//overFileLimit Check if a directory with n entries has too many to be listed
+func (opt Options) overFileLimit(n int) bool {
+	return opt.FileLimit > -1 && n > opt.FileLimit
+}
+
+//arrangeEntries filter & sort the entries of dir as per the options
+func arrangeEntries(dir string, files []os.FileInfo, opt Options) []os.FileInfo {
+	files = applyFilters(dir, files, opt)
+
+	if opt.comparator != nil {
+		sort.SliceStable(files, func(i, j int) bool {
+			return opt.comparator(files[i], files[j]) < 0
+		})
+	}
+	return files
 }
 
 func updateChildrens(tree Tree, childrens []Tree, opt Options, fi os.FileInfo) []Tree {
@@ -80,9 +296,14 @@ func updateChildrens(tree Tree, childrens []Tree, opt Options, fi os.FileInfo) [
 	return childrens
 }
 
-func updateStats(tree Tree, stats Stats) Stats {
+func updateStats(tree Tree, stats Stats, opt Options) Stats {
 	stats.DirCount = stats.DirCount + tree.Stats.DirCount
 	stats.FileCount = stats.FileCount + tree.Stats.FileCount
+	stats.ErrorCount = stats.ErrorCount + tree.Stats.ErrorCount
+	if opt.AggregateSize {
+		stats.Size = stats.Size + tree.Stats.Size
+		stats.DiskUsage = stats.DiskUsage + tree.Stats.DiskUsage
+	}
 	if tree.Root.IsDir() {
 		stats.DirCount++
 	} else {
@@ -91,8 +312,8 @@ func updateStats(tree Tree, stats Stats) Stats {
 	return stats
 }
 
-func applyFilters(fis []os.FileInfo, opt Options) []os.FileInfo {
-	if opt.FileLimit > -1 && len(fis) > opt.FileLimit {
+func applyFilters(dir string, fis []os.FileInfo, opt Options) []os.FileInfo {
+	if opt.overFileLimit(len(fis)) {
 		return []os.FileInfo{}
 	}
 
@@ -100,12 +321,16 @@ func applyFilters(fis []os.FileInfo, opt Options) []os.FileInfo {
 		fis = FilterOutHidden(fis)
 	}
 
-	if len(opt.ExcludePattern) > 0 {
-		fis = FileFilterPattern(fis, opt.ExcludePattern, false)
+	if opt.gitIgnore != nil {
+		fis = FilterGitIgnored(fis, dir, opt.gitIgnore)
+	}
+
+	if opt.patterns != nil {
+		fis = opt.patterns.filter(dir, fis)
 	}
 
-	if len(opt.IncludePattern) > 0 {
-		fis = FileFilterPattern(fis, opt.IncludePattern, true)
+	if opt.predicates != nil {
+		fis = opt.predicates.filter(fis)
 	}
 
 	return fis
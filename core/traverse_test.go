@@ -1,6 +1,11 @@
 package core_test
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -69,7 +74,7 @@ func TestDirStatIncludingHidden(t *testing.T) {
 
 func TestDirStatIncludingPattern(t *testing.T) {
 	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
-	opt.IncludePattern = "*.go"
+	opt.IncludePattern = []string{"*.go"}
 	defer cleaner()
 	tree, error := core.TraverseDir(root, opt, -1)
 	if error != nil {
@@ -86,7 +91,7 @@ func TestDirStatIncludingPattern(t *testing.T) {
 
 func TestDirStatExcludePattern(t *testing.T) {
 	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
-	opt.ExcludePattern = "*.go"
+	opt.ExcludePattern = []string{"*.go"}
 	defer cleaner()
 	tree, error := core.TraverseDir(root, opt, -1)
 	if error != nil {
@@ -103,7 +108,7 @@ func TestDirStatExcludePattern(t *testing.T) {
 
 func TestDirStatIncludingPatternWithMaxLevel(t *testing.T) {
 	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
-	opt.IncludePattern = "*.go"
+	opt.IncludePattern = []string{"*.go"}
 	opt.MaxLevel = 1
 	defer cleaner()
 	tree, error := core.TraverseDir(root, opt, -1)
@@ -118,3 +123,167 @@ func TestDirStatIncludingPatternWithMaxLevel(t *testing.T) {
 		t.Errorf("Expected to get 1 files but got %d", tree.Stats.FileCount)
 	}
 }
+
+func TestParallelTraverseMatchesSequential(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	expected, err := core.TraverseDir(root, opt, -1)
+	if err != nil {
+		t.Errorf("Unable to traverse tree rooted at %s", root)
+	}
+	for _, jobs := range []int{2, 4, 16} {
+		opt.Jobs = jobs
+		tree, err := core.TraverseDir(root, opt, -1)
+		if err != nil {
+			t.Errorf("Unable to traverse tree rooted at %s with %d jobs", root, jobs)
+		}
+		if tree.String() != expected.String() {
+			t.Errorf("Expected %s with %d jobs, got %s", expected, jobs, tree)
+		}
+		if tree.Stats != expected.Stats {
+			t.Errorf("Expected stats %+v with %d jobs, got %+v", expected.Stats, jobs, tree.Stats)
+		}
+	}
+}
+
+func TestDirStatMultiplePatterns(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+
+	table := []struct {
+		include   []string
+		exclude   []string
+		regex     bool
+		matchDirs bool
+		dirs      int
+		files     int
+	}{
+		{[]string{"*.go", "*.py"}, nil, false, false, 5, 5},
+		{[]string{"a/c/**"}, nil, false, false, 5, 2},
+		{[]string{"**/d/*.py"}, nil, false, false, 5, 1},
+		{nil, []string{"*.go", "a/*.py"}, false, false, 5, 1},
+		{[]string{`^a/.*\.py$`}, nil, true, false, 5, 2},
+		{[]string{"c"}, nil, false, true, 5, 2},
+		{nil, []string{"c"}, false, true, 2, 3},
+	}
+
+	for _, test := range table {
+		opt.IncludePattern = test.include
+		opt.ExcludePattern = test.exclude
+		opt.PatternRegex = test.regex
+		opt.MatchDirs = test.matchDirs
+		tree, err := core.TraverseDir(root, opt, -1)
+		if err != nil {
+			t.Errorf("Unable to traverse tree rooted at %s, %v", root, err)
+		}
+		if tree.Stats.DirCount != test.dirs || tree.Stats.FileCount != test.files {
+			t.Errorf("include %v, exclude %v: expected %d directories, %d files but got %d, %d",
+				test.include, test.exclude, test.dirs, test.files, tree.Stats.DirCount, tree.Stats.FileCount)
+		}
+	}
+}
+
+func TestMalformedPattern(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	opt.IncludePattern = []string{"[*.go"}
+	if _, err := core.TraverseDir(root, opt, -1); err == nil {
+		t.Errorf("Expected error for malformed glob")
+	}
+	opt.IncludePattern = []string{"(*.go"}
+	opt.PatternRegex = true
+	if _, err := core.TraverseDir(root, opt, -1); err == nil {
+		t.Errorf("Expected error for malformed regex")
+	}
+}
+
+func TestSortOptions(t *testing.T) {
+	cleaner, opt, root := helper.SetupSortTestDir(uuid.New().String())
+	defer cleaner()
+
+	table := []struct {
+		key        string
+		reverse    bool
+		dirsFirst  bool
+		filesFirst bool
+		expected   string
+	}{
+		{"", false, false, false, "[dir file10.txt file9.md small.go]"},
+		{"name", true, false, false, "[small.go file9.md file10.txt dir]"},
+		{"version", false, false, false, "[dir file9.md file10.txt small.go]"},
+		{"size", false, true, false, "[dir small.go file10.txt file9.md]"},
+		{"size", true, true, false, "[dir file9.md file10.txt small.go]"},
+		{"ext", false, false, false, "[dir small.go file9.md file10.txt]"},
+		{"mtime", false, false, false, "[file9.md file10.txt small.go dir]"},
+		{"mtime", true, false, false, "[dir small.go file10.txt file9.md]"},
+		{"version", true, true, false, "[dir small.go file10.txt file9.md]"},
+		{"version", false, false, true, "[file9.md file10.txt small.go dir]"},
+	}
+	for _, test := range table {
+		opt.SortKey = test.key
+		opt.SortReverse = test.reverse
+		opt.DirsFirst = test.dirsFirst
+		opt.FilesFirst = test.filesFirst
+		tree, err := core.TraverseDir(root, opt, -1)
+		if err != nil {
+			t.Errorf("Unable to traverse tree rooted at %s, %v", root, err)
+		}
+		names := make([]string, len(tree.Childrens))
+		for i, child := range tree.Childrens {
+			names[i] = child.Root.Name()
+		}
+		if fmt.Sprint(names) != test.expected {
+			t.Errorf("Sort %q reverse %v: expected %s, got %v", test.key, test.reverse, test.expected, names)
+		}
+	}
+
+	opt.SortKey = "colour"
+	if _, err := core.TraverseDir(root, opt, -1); err == nil {
+		t.Errorf("Expected error for invalid sort key")
+	}
+}
+
+func TestTraverseDirContinuesPastErrors(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	os.Symlink("loop", filepath.Join(root, "a", "loop"))
+	// Root can read any directory, so the permission case only runs as user
+	locked := os.Geteuid() != 0
+	if locked {
+		os.Chmod(filepath.Join(root, "a", "c"), 0)
+		defer os.Chmod(filepath.Join(root, "a", "c"), 0755)
+	}
+	opt.FollowLink = true
+	opt.NoReport = false
+	tree, err := core.TraverseDir(root, opt, 0)
+	if err != nil {
+		t.Fatalf("Expected traversal to continue, got %v", err)
+	}
+	buf := new(bytes.Buffer)
+	tree.Print(buf, opt)
+	if !strings.Contains(buf.String(), "loop [error: too many levels of symbolic links]") {
+		t.Errorf("Expected loop of links to be annotated, got\n%s", buf)
+	}
+	expected := 1
+	if locked {
+		expected = 2
+		if !strings.Contains(buf.String(), "c [error opening dir: permission denied]") {
+			t.Errorf("Expected locked directory to be annotated, got\n%s", buf)
+		}
+	}
+	if tree.Stats.ErrorCount != expected || !strings.Contains(buf.String(), fmt.Sprintf(", %d error", expected)) {
+		t.Errorf("Expected %d errors in the report, got %+v\n%s", expected, tree.Stats, buf)
+	}
+
+	// The error survives a JSON round trip
+	opt.JSONIncludeStats = true
+	data, _ := tree.AsJSONString(opt)
+	loaded, err := core.LoadJSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unable to load JSON, %v", err)
+	}
+	if loaded.Stats.ErrorCount != expected {
+		t.Errorf("Expected %d errors after loading, got %+v", expected, loaded.Stats)
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"reflect"
 	"strings"
 	"time"
 )
@@ -23,9 +22,32 @@ import (
 type Stats struct {
 	DirCount         int       `json:"dir_count"`
 	FileCount        int       `json:"file_count"`
+	ErrorCount       int       `json:"error_count"`
 	Size             int64     `json:"size"`
+	DiskUsage        int64     `json:"disk_usage"`
 	ModificationTime time.Time `json:"mod_time"`
 	Permission       string    `json:"permission"`
+	UID              string    `json:"uid,omitempty"`
+	GID              string    `json:"gid,omitempty"`
+	Owner            string    `json:"owner,omitempty"`
+	Group            string    `json:"group,omitempty"`
+	Mode             string    `json:"mode,omitempty"`
+	Inode            uint64    `json:"inode,omitempty"`
+	Links            uint64    `json:"links,omitempty"`
+	Device           uint64    `json:"device,omitempty"`
+	ChangeTime       time.Time `json:"change_time"`
+	AccessTime       time.Time `json:"access_time"`
+	Digest           string    `json:"digest,omitempty"`
+	DigestAlgorithm  string    `json:"digest_algorithm,omitempty"`
+
+	// present is false when only the counts are known, e.g. for nodes of a
+	// snapshot exported without stats
+	present bool
+}
+
+//Present Check if the stats of the node are known beside the counts
+func (stats Stats) Present() bool {
+	return stats.present
 }
 
 //NewEmptyStats ...
@@ -34,8 +56,25 @@ func NewEmptyStats(fi os.FileInfo) Stats {
 		DirCount:         0,
 		FileCount:        0,
 		Size:             fi.Size(),
+		DiskUsage:        diskUsage(fi),
 		ModificationTime: fi.ModTime(),
 		Permission:       fi.Mode().Perm().String(),
+		Mode:             ModeString(fi.Mode()),
+		ChangeTime:       changeTime(fi),
+		present:          true,
+	}
+	stats.setOwner(fi)
+	stats.Inode, stats.Links, stats.Device, _ = fileIdentity(fi)
+	return stats
+}
+
+//nodeStats Stats of fi for the traversal. Reading the tree changes the access
+//time of directories, so it is only collected when it is printed or exported
+//along with the other stats.
+func nodeStats(fi os.FileInfo, opt Options) Stats {
+	stats := NewEmptyStats(fi)
+	if opt.PrintAccessTime || opt.JSONIncludeStats {
+		stats.AccessTime = accessTime(fi)
 	}
 	return stats
 }
@@ -43,18 +82,35 @@ func NewEmptyStats(fi os.FileInfo) Stats {
 // Tree Data Model representing a file/directory as Root and Its childrens(in case of directory).
 // It also contains stats of the Root, which currently only have count of all directories and files
 // in the Root. Going further we can ustilize Stat to include few more details like space used etc.
+//
+// Err is set when the node could not be read, e.g. a directory without
+// permission to list it, in which case it has no childrens. Link is set when the
+// node is a symbolic link. DuplicateOf is the path of an other file with the
+// same content, set when duplicates are marked.
 type Tree struct {
-	Root      os.FileInfo
-	Childrens []Tree
-	Stats     Stats
+	Root        os.FileInfo
+	Childrens   []Tree
+	Stats       Stats
+	Err         error
+	Link        *Link
+	DuplicateOf string
+
+	// unread is set for directories whose entries were not read because of
+	// MaxLevel or FileLimit, their content is unknown
+	unread bool
 }
 
 //JSONTree Json Representation of Tree
 type JSONTree struct {
-	Name    string     `json:"name"`
-	FType   FileType   `json:"file_type"`
-	FStats  *Stats     `json:"stats,omitempty"`
-	SubTree []JSONTree `json:"subtree"`
+	Name        string     `json:"name"`
+	FType       FileType   `json:"file_type"`
+	FStats      *Stats     `json:"stats,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Target      string     `json:"target,omitempty"`
+	Dangling    bool       `json:"dangling,omitempty"`
+	Recursive   bool       `json:"recursive,omitempty"`
+	DuplicateOf string     `json:"duplicate_of,omitempty"`
+	SubTree     []JSONTree `json:"subtree"`
 }
 
 // String Implements String method of Stringer interface, helpful in debugging.
@@ -72,68 +128,125 @@ func (tree Tree) String() string {
 // Later we will few more mthods on tree which will allow to output tree result
 // to other means like file or socket etc.
 func (tree Tree) Print(w io.Writer, opt Options) {
-	tree.printTree(w, opt, 0, false)
+	opt.widths = columnWidths(tree, opt)
+	tree.printTree(w, opt, nil)
 	if !opt.NoReport {
-		fmt.Fprintf(w, "\n%d directories, %d files\n", tree.Stats.DirCount, tree.Stats.FileCount)
+		fmt.Fprintf(w, "\n%s\n", report(tree.Stats, opt))
 	}
 }
 
-//printTree Helper private method to recursively print tree on console
-func (tree Tree) printTree(w io.Writer, opt Options, padding int, isLastChild bool) {
+//report Helper private method to build the final report line, which includes
+//the total size when sizes are aggregated
+func report(stats Stats, opt Options) string {
+	counts := fmt.Sprintf("%d directories, %d files", stats.DirCount, stats.FileCount)
+	if stats.ErrorCount == 1 {
+		counts += ", 1 error"
+	} else if stats.ErrorCount > 1 {
+		counts += fmt.Sprintf(", %d errors", stats.ErrorCount)
+	}
+	if opt.AggregateSize {
+		return fmt.Sprintf("%s used in %s", formatSize(sizeOf(stats, opt), opt), counts)
+	}
+	return counts
+}
+
+//sizeOf Size of the node as per options, either apparent size or disk usage
+func sizeOf(stats Stats, opt Options) int64 {
+	if opt.PrintBlocks {
+		return stats.DiskUsage
+	}
+	return stats.Size
+}
+
+//formatSize Format size in bytes, or in human readable units of 1024 (--human)
+//or 1000 (--si) like 4.0K, 12M
+func formatSize(size int64, opt Options) string {
+	if !opt.HumanReadable && !opt.SIUnits {
+		return fmt.Sprintf("%d", size)
+	}
+	unit, suffixes := 1024.0, "KMGTPE"
+	if opt.SIUnits {
+		unit, suffixes = 1000.0, "kMGTPE"
+	}
+	value := float64(size)
+	if value < unit {
+		return fmt.Sprintf("%d", size)
+	}
+	i := -1
+	for value >= unit && i < len(suffixes)-1 {
+		value = value / unit
+		i++
+	}
+	if value < 10 {
+		return fmt.Sprintf("%.1f%c", value, suffixes[i])
+	}
+	return fmt.Sprintf("%.0f%c", value, suffixes[i])
+}
+
+//printTree Helper private method to recursively print tree on console, last
+//tells for the node and each of its ancestors whether it is the last child,
+//which decides the branches drawn in front of its children
+func (tree Tree) printTree(w io.Writer, opt Options, last []bool) {
 	tree.printNode(w, opt)
 	l := len(tree.Childrens)
 	for index, subtree := range tree.Childrens {
-		i := 0
 		if canPrune(subtree, opt) {
 			continue
 		}
-		for i < padding {
-			if i+1 == padding && isLastChild {
-				fmt.Fprintf(w, "%s", " ")
-			} else {
-				fmt.Fprintf(w, "%s", opt.PipeColor("│"))
-			}
-			fmt.Fprintf(w, "%s", strings.Repeat(" ", 2))
-			i = i + 1
-		}
-
-		if l == index+1 {
-			fmt.Fprintf(w, "%s", opt.LLinkColor("└──"))
-		} else {
-			fmt.Fprintf(w, "%s", opt.TLinkColor("├──"))
-		}
-		subtree.printTree(w, opt, padding+1, index+1 == l)
+		childLast := append(last[:len(last):len(last)], index+1 == l)
+		fmt.Fprintf(w, "%s", streamPrefix(childLast, opt))
+		subtree.printTree(w, opt, childLast)
 	}
 }
 
 //getColor Helper method to get color based on file type
 func (tree Tree) getColor(opt Options) Colorize {
-	if tree.Root.IsDir() {
+	if opt.LSColors != nil {
+		if colorize, ok := opt.LSColors.colorOf(tree); ok {
+			return colorize
+		}
+	}
+	if tree.Link != nil {
+		return opt.SymLinkColor
+	}
+	switch GetFileType(tree.Root) {
+	case DIR:
 		return opt.DirColor
+	case FIFO:
+		return opt.FIFOColor
+	case SOCKET:
+		return opt.SocketColor
+	case CHARDEV, BLOCKDEV:
+		return opt.DeviceColor
+	}
+	if IsExecutable(tree.Root) {
+		return opt.ExecColor
 	}
 	return opt.FileColor
 }
 
-//GetExtra ...
-func GetExtra(tree Tree, opt Options) string {
-	extra := make([]string, 0)
-	sys := tree.Root.Sys()
-	if opt.PrintUID {
-		extra = append(extra, fmt.Sprintf("%v", reflect.ValueOf(sys).Elem().FieldByName("Uid")))
-	}
-	if opt.PrintGID {
-		extra = append(extra, fmt.Sprintf("%v", reflect.ValueOf(sys).Elem().FieldByName("Gid")))
+//classify Helper private method to append the type indicator to the name and
+//to the target of links, when classify is enabled. Indicator of a link which is
+//followed is the one of its target.
+func (tree Tree) classify(name string, opt Options) (string, string) {
+	if tree.Link == nil {
+		if !opt.Classify {
+			return name, ""
+		}
+		return name + typeIndicator(tree.Root), ""
 	}
-	if opt.PrintSize {
-		extra = append(extra, fmt.Sprintf("%d", tree.Stats.Size))
+	if !opt.Classify {
+		return name, tree.Link.Target
 	}
-	if opt.PrintModTime {
-		extra = append(extra, tree.Stats.ModificationTime.Format(opt.TimeFormat))
+	if GetFileType(tree.Root) != LINK {
+		return name, tree.Link.Target + typeIndicator(tree.Root)
 	}
+	return name + FTypeIndicator[LINK], tree.Link.Target
+}
 
-	if opt.PrintProtection {
-		extra = append(extra, tree.Stats.Permission)
-	}
+//GetExtra ...
+func GetExtra(tree Tree, opt Options) string {
+	extra := alignColumns(extraColumns(tree, opt), opt)
 	res := strings.Join(extra, " ")
 	if len(extra) >= 1 {
 		return fmt.Sprintf("[ %s ]", res)
@@ -148,7 +261,42 @@ func (tree Tree) printNode(w io.Writer, opt Options) {
 	if err != nil {
 		panic(err)
 	}
-	fmt.Fprintf(w, "%s%s\n", colorize(GetExtra(tree, opt)), colorize(path))
+	name, target := tree.classify(path, opt)
+	fmt.Fprintf(w, "%s%s", colorize(GetExtra(tree, opt)), colorize(name))
+	if tree.Link != nil {
+		fmt.Fprintf(w, " -> %s", target)
+		if tree.Link.Dangling {
+			fmt.Fprintf(w, " %s", opt.ErrorColor("[dangling]"))
+		}
+		if tree.Link.Recursive {
+			fmt.Fprintf(w, " %s", opt.ErrorColor("[recursive, not followed]"))
+		}
+	}
+	if tree.DuplicateOf != "" {
+		fmt.Fprintf(w, " %s", opt.ErrorColor(fmt.Sprintf("[duplicate of %s]", tree.DuplicateOf)))
+	}
+	if tree.Err != nil {
+		fmt.Fprintf(w, " %s", opt.ErrorColor(fmt.Sprintf("[%s]", errorLabel(tree))))
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+//errorLabel Helper private method to describe the error of the node like GNU
+//tree does, followed by the reason
+func errorLabel(tree Tree) string {
+	if tree.Root.IsDir() {
+		return fmt.Sprintf("error opening dir: %s", errorReason(tree.Err))
+	}
+	return fmt.Sprintf("error: %s", errorReason(tree.Err))
+}
+
+//errorReason Helper private method to get the reason of err without the path,
+//which is already known from the node
+func errorReason(err error) string {
+	if pe, ok := err.(*os.PathError); ok {
+		return pe.Err.Error()
+	}
+	return err.Error()
 }
 
 //NodeName Get NodeName of the tree
@@ -173,7 +321,15 @@ func (tree Tree) AsJSONTree(opt Options) JSONTree {
 	name, _ := tree.NodeName(opt)
 	fileType := GetFileType(tree.Root)
 	jsonTree := JSONTree{Name: name, FType: fileType, SubTree: []JSONTree{}}
-	if opt.JSONIncludeStats {
+	if tree.Err != nil {
+		jsonTree.Error = errorReason(tree.Err)
+	}
+	if tree.Link != nil {
+		jsonTree.Target = tree.Link.Target
+		jsonTree.Dangling, jsonTree.Recursive = tree.Link.Dangling, tree.Link.Recursive
+	}
+	jsonTree.DuplicateOf = tree.DuplicateOf
+	if opt.JSONIncludeStats && tree.Stats.Present() {
 		jsonTree.FStats = &tree.Stats
 	}
 	for _, subtree := range tree.Childrens {
@@ -4,7 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -95,6 +101,35 @@ func TestTreePrint_level(t *testing.T) {
 	}
 }
 
+func TestTreePrint_ascii(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	opt.MaxLevel = 1
+	opt.Charset = "ascii"
+	tree, err := core.TraverseDir(root, opt, -1)
+	if err != nil {
+		t.Errorf("Unable to traverse the Dir")
+	}
+	buf := new(bytes.Buffer)
+	tree.Print(buf, opt)
+	output := []byte(fmt.Sprintf(`%s
+|--a
+|  |--b
+|  |--c
+|  `+"`"+`--normal.py
+`+"`"+`--normal.go
+
+3 directories, 2 files
+`, root_file))
+	if !bytes.Equal(output, buf.Bytes()) {
+		t.Errorf("expected %s, got: \n%s", output, buf.Bytes())
+	}
+	if err := core.ValidateCharset("ebcdic"); err == nil {
+		t.Errorf("Expected error for unknown charset")
+	}
+}
+
 func TestJsonTree(t *testing.T) {
 	root_file := uuid.New().String()
 	cleaner, opt, root := helper.SetupTestDir(root_file)
@@ -195,3 +230,90 @@ func TestJsonTree_prune(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expectedJSONTree, jsonTree)
 	}
 }
+
+func TestGetExtra_humanReadableSize(t *testing.T) {
+	opt := core.DefaultOptions()
+	opt.PrintSize = true
+	table := []struct {
+		size  int64
+		human bool
+		si    bool
+		extra string
+	}{
+		{1023, true, false, "[ 1023 ]"},
+		{1536, false, false, "[ 1536 ]"},
+		{1536, true, false, "[ 1.5K ]"},
+		{1536, false, true, "[ 1.5k ]"},
+		{12 * 1024 * 1024, true, false, "[ 12M ]"},
+		{5 * 1000 * 1000 * 1000, false, true, "[ 5.0G ]"},
+	}
+	for _, test := range table {
+		opt.HumanReadable = test.human
+		opt.SIUnits = test.si
+		extra := core.GetExtra(core.Tree{Stats: core.Stats{Size: test.size}}, opt)
+		if extra != test.extra {
+			t.Errorf("Expected %s for %d bytes, got %s", test.extra, test.size, extra)
+		}
+	}
+}
+
+func TestAggregateSize(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	ioutil.WriteFile(filepath.Join(root, "a", "c", "d", "normal.py"), make([]byte, 3000), 0666)
+	ioutil.WriteFile(filepath.Join(root, "normal.go"), make([]byte, 700), 0666)
+	opt.AggregateSize = true
+	tree, err := core.TraverseDir(root, opt, -1)
+	if err != nil {
+		t.Errorf("Unable to traverse the Dir")
+	}
+	var expected int64
+	filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if !strings.HasPrefix(fi.Name(), ".") {
+			expected += fi.Size()
+		}
+		return nil
+	})
+	if tree.Stats.Size != expected {
+		t.Errorf("Expected aggregated size %d, got %d", expected, tree.Stats.Size)
+	}
+	buf := new(bytes.Buffer)
+	tree.Print(buf, opt)
+	if !strings.HasSuffix(buf.String(), fmt.Sprintf("\n%d used in 5 directories, 5 files\n", expected)) {
+		t.Errorf("Expected report with total size, got %s", buf.String())
+	}
+}
+
+func TestGetExtra_owner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Ownership by numeric ids is not available on windows")
+	}
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	current, err := user.Current()
+	if err != nil {
+		t.Skip("Unable to look up the current user")
+	}
+	group, err := user.LookupGroupId(current.Gid)
+	if err != nil {
+		t.Skip("Unable to look up the group of current user")
+	}
+	opt.MaxLevel = 0
+	opt.PrintUID, opt.PrintGID = true, true
+	tree, _ := core.TraverseDir(filepath.Join(root, "normal.go"), opt, 0)
+	if extra := core.GetExtra(tree, opt); extra != fmt.Sprintf("[ %s %s ]", current.Username, group.Name) {
+		t.Errorf("Expected names of current user & group, got %s", extra)
+	}
+	opt.NumericIDs = true
+	if extra := core.GetExtra(tree, opt); extra != fmt.Sprintf("[ %s %s ]", current.Uid, current.Gid) {
+		t.Errorf("Expected ids of current user & group, got %s", extra)
+	}
+
+	// Owner is exported in JSON stats, nodes without owner print -
+	if tree.Stats.Owner != current.Username || tree.Stats.Group != group.Name {
+		t.Errorf("Expected owner in stats, got %+v", tree.Stats)
+	}
+	if extra := core.GetExtra(core.Tree{}, opt); extra != "[ - - ]" {
+		t.Errorf("Expected unknown owner, got %s", extra)
+	}
+}
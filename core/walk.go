@@ -0,0 +1,51 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+)
+
+// SkipDir Returned by a WalkFunc to skip the content of the directory it was
+// called for. It is the same value as filepath.SkipDir.
+var SkipDir = filepath.SkipDir
+
+// WalkEntry A node visited by Walk
+type WalkEntry struct {
+	// Path of the node, joined to the root given to Walk
+	Path string
+	// Parent path of the parent directory, empty for the root
+	Parent string
+	// Depth of the node below the root, which is at depth 0
+	Depth int
+	// Info of the node, as returned by Readdir when the node itself could
+	// not be stat'ed and nil only if the root could not be stat'ed
+	Info os.FileInfo
+	// Err is set when the node could not be stat'ed, or when it is a
+	// directory whose content could not be read (e.g. permission denied)
+	Err error
+	// Link is set when the node is a symbolic link, Link.Recursive marks
+	// directories which are not walked again
+	Link *Link
+}
+
+// WalkFunc Visitor called by Walk for every node. When entry.Err is set,
+// returning nil skips the failed node and continues the walk while returning an
+// error (typically entry.Err) aborts it. Returning SkipDir for a directory skips
+// its content, any other error aborts the walk and is returned by Walk.
+type WalkFunc func(entry WalkEntry) error
+
+// Walk walks the directory rooted at root in the order the tree is printed,
+// calling fn for every node as soon as it is discovered, with the filtering,
+// sorting, level & dironly options applied like TraverseDir. Directories are
+// read before fn is called for them, so a directory which could not be read is
+// visited once with Err set. The walk stops with ctx.Err() as soon as ctx is
+// done. Returned Stats hold the count of directories & files visited.
+// opt.Prune, opt.AggregateSize and opt.Jobs are ignored, and directories are
+// visited even when none of their entries match the predicates. An error is
+// returned when opt.Hash or opt.MarkDuplicates is set.
+func Walk(ctx context.Context, root string, opt Options, fn WalkFunc) (Stats, error) {
+	return streamWalk(ctx, root, opt, func(entry streamEntry) error {
+		return fn(WalkEntry{Path: entry.path, Parent: entry.parent, Depth: entry.depth, Info: entry.fi, Err: entry.err, Link: entry.link})
+	})
+}
@@ -0,0 +1,110 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestWalk(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	visited := make([]string, 0)
+	stats, err := core.Walk(context.Background(), root, opt, func(entry core.WalkEntry) error {
+		rel, _ := filepath.Rel(root, entry.Path)
+		visited = append(visited, fmt.Sprintf("%d %s", entry.Depth, rel))
+		if entry.Info.Name() == "c" {
+			return core.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Unable to walk the Dir, %v", err)
+	}
+	expected := "[0 . 1 a 2 a/b 3 a/b/normal.go 2 a/c 2 a/normal.py 1 normal.go]"
+	if fmt.Sprint(visited) != expected {
+		t.Errorf("Expected %s, got %s", expected, visited)
+	}
+	if stats.DirCount != 3 || stats.FileCount != 3 {
+		t.Errorf("Expected 3 directories & 3 files, got %+v", stats)
+	}
+}
+
+func TestWalk_errors(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	os.Symlink("loop", filepath.Join(root, "a", "loop"))
+	opt.FollowLink = true
+
+	// Skipping the failed entry continues the walk
+	failed := make([]string, 0)
+	stats, err := core.Walk(context.Background(), root, opt, func(entry core.WalkEntry) error {
+		if entry.Err != nil {
+			failed = append(failed, entry.Info.Name())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected walk to continue, got %v", err)
+	}
+	if fmt.Sprint(failed) != "[loop]" || stats.FileCount != 6 {
+		t.Errorf("Expected loop to fail & 6 files, got %s %+v", failed, stats)
+	}
+
+	// Returning the error aborts the walk
+	_, err = core.Walk(context.Background(), root, opt, func(entry core.WalkEntry) error {
+		return entry.Err
+	})
+	if err == nil {
+		t.Errorf("Expected walk to abort with the error of loop")
+	}
+}
+
+func TestWalk_wholeTreeOptions(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+
+	// Digests & duplicates need the whole tree, they are refused
+	for _, set := range []func(*core.Options){
+		func(opt *core.Options) { opt.Hash = "sha256" },
+		func(opt *core.Options) { opt.MarkDuplicates = true },
+	} {
+		o := opt
+		set(&o)
+		visited := 0
+		_, err := core.Walk(context.Background(), root, o, func(entry core.WalkEntry) error {
+			visited++
+			return nil
+		})
+		if err == nil || visited != 0 {
+			t.Errorf("Expected an error before visiting, got %v after %d entries", err, visited)
+		}
+	}
+}
+
+func TestWalk_cancel(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	ctx, cancel := context.WithCancel(context.Background())
+	count := 0
+	_, err := core.Walk(ctx, root, opt, func(entry core.WalkEntry) error {
+		count++
+		if entry.Depth == 1 {
+			cancel()
+		}
+		return nil
+	})
+	if err != context.Canceled || count != 2 {
+		t.Errorf("Expected walk to stop after 2 entries with %v, got %d entries & %v", context.Canceled, count, err)
+	}
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// xmlNode XML representation of a tree node following the schema of GNU
+// tree -X, where the element name is the type of the node
+type xmlNode struct {
+	XMLName   xml.Name
+	Name      string    `xml:"name,attr"`
+	Target    string    `xml:"target,attr,omitempty"`
+	Mode      string    `xml:"mode,attr,omitempty"`
+	Prot      string    `xml:"prot,attr,omitempty"`
+	User      string    `xml:"user,attr,omitempty"`
+	Group     string    `xml:"group,attr,omitempty"`
+	Size      string    `xml:"size,attr,omitempty"`
+	Time      string    `xml:"time,attr,omitempty"`
+	Error     string    `xml:"error,omitempty"`
+	Childrens []xmlNode `xml:",omitempty"`
+}
+
+// xmlReport Count of directories & files at the end of the XML document
+type xmlReport struct {
+	Directories int `xml:"directories"`
+	Files       int `xml:"files"`
+}
+
+// xmlTree Root element of the XML document
+type xmlTree struct {
+	XMLName xml.Name `xml:"tree"`
+	Root    xmlNode
+	Report  *xmlReport `xml:"report,omitempty"`
+}
+
+// xmlElements Element name by the type of the node
+var xmlElements = map[FileType]string{
+	FILE:     "file",
+	DIR:      "directory",
+	LINK:     "link",
+	FIFO:     "fifo",
+	SOCKET:   "socket",
+	CHARDEV:  "char",
+	BLOCKDEV: "block",
+}
+
+// AsXML Write the tree as XML compatible with the output of GNU tree -X. The
+// optional mode, prot, user, group, size & time attributes are driven by
+// PrintProtection, PrintUID, PrintGID, PrintSize & PrintModTime options.
+func (tree Tree) AsXML(w io.Writer, opt Options) error {
+	name, err := tree.NodeName(opt)
+	if err != nil {
+		return err
+	}
+	doc := xmlTree{Root: tree.asXMLNode(name, opt)}
+	if !opt.NoReport {
+		doc.Report = &xmlReport{Directories: tree.Stats.DirCount, Files: tree.Stats.FileCount}
+	}
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, out)
+	return err
+}
+
+// asXMLNode Helper private method to build XML representation of the node
+func (tree Tree) asXMLNode(name string, opt Options) xmlNode {
+	node := xmlNode{XMLName: xml.Name{Local: xmlElements[GetFileType(tree.Root)]}, Name: name}
+	if node.XMLName.Local == "" {
+		node.XMLName.Local = xmlElements[FILE]
+	}
+	if tree.Link != nil {
+		node.XMLName.Local, node.Target = xmlElements[LINK], tree.Link.Target
+	}
+	if opt.PrintProtection {
+		node.Mode = fmt.Sprintf("%04o", tree.Root.Mode().Perm())
+		node.Prot = modeOf(tree.Stats, opt)
+	}
+	if opt.PrintUID {
+		node.User = ownerOf(tree.Stats, opt)
+	}
+	if opt.PrintGID {
+		node.Group = groupOf(tree.Stats, opt)
+	}
+	if opt.PrintSize {
+		node.Size = fmt.Sprintf("%d", sizeOf(tree.Stats, opt))
+	}
+	if opt.PrintModTime {
+		node.Time = tree.Stats.ModificationTime.Format(opt.TimeFormat)
+	}
+	if tree.Err != nil {
+		node.Error = errorReason(tree.Err)
+	}
+	for _, subtree := range tree.Childrens {
+		if canPrune(subtree, opt) {
+			continue
+		}
+		node.Childrens = append(node.Childrens, subtree.asXMLNode(subtree.Root.Name(), opt))
+	}
+	return node
+}
@@ -0,0 +1,69 @@
+package core_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marshal003/hitree/core"
+	"github.com/marshal003/hitree/core/helper"
+)
+
+func TestTreeAsXML(t *testing.T) {
+	root_file := uuid.New().String()
+	cleaner, opt, root := helper.SetupTestDir(root_file)
+	defer cleaner()
+	opt.MaxLevel = 2
+	tree, err := core.TraverseDir(root, opt, 0)
+	if err != nil {
+		t.Errorf("Unable to traverse the Dir")
+	}
+	buf := new(bytes.Buffer)
+	if err := tree.AsXML(buf, opt); err != nil {
+		t.Errorf("Unable to render XML, %v", err)
+	}
+	output := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<tree>
+  <directory name="%s">
+    <directory name="a">
+      <directory name="b"></directory>
+      <directory name="c"></directory>
+      <file name="normal.py"></file>
+    </directory>
+    <file name="normal.go"></file>
+  </directory>
+  <report>
+    <directories>3</directories>
+    <files>2</files>
+  </report>
+</tree>
+`, root_file)
+	if buf.String() != output {
+		t.Errorf("expected %s, got: \n%s", output, buf.String())
+	}
+}
+
+func TestFileAsXMLWithAttributes(t *testing.T) {
+	cleaner, opt, root := helper.SetupTestDir(uuid.New().String())
+	defer cleaner()
+	opt.PrintProtection = true
+	opt.PrintSize = true
+	opt.NoReport = true
+	tree, err := core.TraverseDir(root+"/normal.go", opt, 0)
+	if err != nil {
+		t.Errorf("Unable to traverse the file")
+	}
+	buf := new(bytes.Buffer)
+	if err := tree.AsXML(buf, opt); err != nil {
+		t.Errorf("Unable to render XML, %v", err)
+	}
+	output := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<tree>
+  <file name="normal.go" mode="%04o" prot="%s" size="0"></file>
+</tree>
+`, tree.Root.Mode().Perm(), tree.Stats.Mode)
+	if buf.String() != output {
+		t.Errorf("expected %s, got: \n%s", output, buf.String())
+	}
+}